@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pleska/ham-radio-assistant/internal/api"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+)
+
+// command is a subcommand that runs a tool without an MCP client
+type command struct {
+	usage string
+	// build parses the command's arguments into a tool name and arguments
+	build func(fs *flag.FlagSet, args []string) (string, map[string]any, error)
+}
+
+// commands are the subcommands by name
+var commands = map[string]command{
+	"lookup": {
+		usage: "lookup [flags] CALLSIGN",
+		build: func(fs *flag.FlagSet, args []string) (string, map[string]any, error) {
+			positional, err := parseFlags(fs, args)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(positional) != 1 {
+				return "", nil, errors.New("expected one callsign")
+			}
+			return "callsign-lookup", map[string]any{"callsign": positional[0]}, nil
+		},
+	},
+	"bearing": {
+		usage: "bearing [flags] [--from LOCATION] --to LOCATION",
+		build: func(fs *flag.FlagSet, args []string) (string, map[string]any, error) {
+			from := fs.String("from", "", "Origin grid square, latitude,longitude or callsign (default your station)")
+			to := fs.String("to", "", "Destination grid square, latitude,longitude or callsign")
+			model := fs.String("model", "", "Distance model: spherical or ellipsoid")
+			positional, err := parseFlags(fs, args)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(positional) != 0 {
+				return "", nil, fmt.Errorf("unexpected argument %q", positional[0])
+			}
+			if *to == "" {
+				return "", nil, errors.New("--to must be given")
+			}
+			return bearingCall(*from, *to, *model)
+		},
+	},
+	"park": {
+		usage: "park [flags] REFERENCE",
+		build: func(fs *flag.FlagSet, args []string) (string, map[string]any, error) {
+			positional, err := parseFlags(fs, args)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(positional) != 1 {
+				return "", nil, errors.New("expected one park reference")
+			}
+			return "pota-park-lookup", map[string]any{"reference": strings.ToUpper(positional[0])}, nil
+		},
+	},
+	"spots": {
+		usage: "spots [flags]",
+		build: func(fs *flag.FlagSet, args []string) (string, map[string]any, error) {
+			activator := fs.String("callsign", "", "Activator callsign to filter by")
+			mode := fs.String("mode", "", "Mode to filter by (e.g. SSB, CW, FT8)")
+			band := fs.String("band", "", "Band to filter by (e.g. 20m, 40m, 2m)")
+			location := fs.String("location", "", "Park location to filter by (e.g. US-TX)")
+			maxDistance := fs.String("max-distance", "", "Only list parks within this distance of your station")
+			order := fs.String("sort", "", "Order of the spots: time, distance or frequency")
+			limit := fs.String("limit", "", "Maximum number of spots to list")
+			positional, err := parseFlags(fs, args)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(positional) != 0 {
+				return "", nil, fmt.Errorf("unexpected argument %q", positional[0])
+			}
+			toolArgs := map[string]any{}
+			setIfNotEmpty(toolArgs, "callsign", *activator)
+			setIfNotEmpty(toolArgs, "mode", *mode)
+			setIfNotEmpty(toolArgs, "band", *band)
+			setIfNotEmpty(toolArgs, "location", *location)
+			setIfNotEmpty(toolArgs, "max-distance", *maxDistance)
+			setIfNotEmpty(toolArgs, "sort", *order)
+			setIfNotEmpty(toolArgs, "limit", *limit)
+			return "pota-spots", toolArgs, nil
+		},
+	},
+	"call": {
+		usage: "call [flags] TOOL [NAME=VALUE ...]",
+		build: func(fs *flag.FlagSet, args []string) (string, map[string]any, error) {
+			positional, err := parseFlags(fs, args)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(positional) == 0 {
+				return "", nil, errors.New("expected a tool name")
+			}
+			toolArgs := map[string]any{}
+			for _, arg := range positional[1:] {
+				name, value, ok := strings.Cut(arg, "=")
+				if !ok || name == "" {
+					return "", nil, fmt.Errorf("invalid tool argument %q (expected NAME=VALUE)", arg)
+				}
+				toolArgs[name] = value
+			}
+			return positional[0], toolArgs, nil
+		},
+	},
+}
+
+// commandNames lists the subcommands in the order they are documented
+var commandNames = []string{"lookup", "bearing", "park", "spots", "call"}
+
+// runCommand runs a subcommand and returns the process exit code
+func runCommand(ctx context.Context, server *api.Server, name string, args []string, stdout, stderr io.Writer) int {
+	cmd := commands[name]
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("output", "markdown", "Output format: markdown, json or csv (tables only)")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: ham-radio-assistant %s\n\nFlags:\n", cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	tool, toolArgs, err := cmd.build(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n\n", err)
+		fs.Usage()
+		return 2
+	}
+	toolArgs["output"] = *output
+
+	result, err := server.CallTool(ctx, tool, toolArgs)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	out := stdout
+	if result.IsError {
+		out = stderr
+	}
+	for _, content := range result.Content {
+		switch content := content.(type) {
+		case mcp.TextContent:
+			fmt.Fprintln(out, content.Text)
+		case mcp.ImageContent:
+			fmt.Fprintf(stderr, "(%s image not shown)\n", content.MIMEType)
+		}
+	}
+	if result.IsError {
+		return 1
+	}
+	return 0
+}
+
+// parseFlags parses flags that may appear before, between or after the
+// positional arguments and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// bearingCall chooses the bearing tool for the given locations: the antenna
+// bearing tool for grid squares and coordinates, and the callsign bearing
+// tool for callsigns
+func bearingCall(from, to, model string) (string, map[string]any, error) {
+	args := map[string]any{}
+	setIfNotEmpty(args, "model", model)
+
+	fromCallsign := from != "" && !isPosition(from)
+	toCallsign := !isPosition(to)
+	if fromCallsign || toCallsign {
+		if !toCallsign || (from != "" && !fromCallsign) {
+			return "", nil, errors.New("--from and --to must both be callsigns or both be positions")
+		}
+		setIfNotEmpty(args, "origin-callsign", from)
+		args["destination-callsign"] = to
+		return "callsign-bearing", args, nil
+	}
+
+	if from != "" {
+		setPosition(args, "origin", from)
+	}
+	setPosition(args, "destination", to)
+	return "antenna-bearing", args, nil
+}
+
+// isPosition reports whether a location is a grid square or coordinates
+// rather than a callsign
+func isPosition(location string) bool {
+	if strings.Contains(location, ",") {
+		return true
+	}
+	_, err := maidenhead.Parse(location)
+	return err == nil
+}
+
+// setPosition sets the tool arguments for a grid square or coordinates
+func setPosition(args map[string]any, name, location string) {
+	if lat, lon, ok := strings.Cut(location, ","); ok {
+		args[name+"-latitude"] = strings.TrimSpace(lat)
+		args[name+"-longitude"] = strings.TrimSpace(lon)
+		return
+	}
+	args[name+"-grid"] = location
+}
+
+// setIfNotEmpty sets a tool argument when the value is not empty
+func setIfNotEmpty(args map[string]any, name, value string) {
+	if value != "" {
+		args[name] = value
+	}
+}
+
+// printUsage describes the server flags and the subcommands
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n  ham-radio-assistant [flags]            Serve the MCP tools\n")
+	for _, name := range commandNames {
+		fmt.Fprintf(out, "  ham-radio-assistant %s\n", commands[name].usage)
+	}
+	fmt.Fprintf(out, "\nServer flags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(out, "\nRun a command with -h for its flags.\n")
+}
+
+// isCommand reports whether the argument names a subcommand
+func isCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
@@ -1,35 +1,112 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	// Embed the time zone database for the station time zone, since the
+	// runtime image has none
+	_ "time/tzdata"
 
 	"github.com/pleska/ham-radio-assistant/internal/api"
 	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/logging"
 )
 
 func main() {
-	// Get executable directory to find config relative to it
-	execPath, err := os.Executable()
+	transport := flag.String("transport", api.TransportStdio, "MCP transport to serve: stdio, sse or http")
+	configPath := flag.String("config", os.Getenv(config.ConfigEnv), "Config file (default config.json next to the executable, if present)")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	// A subcommand runs a single tool instead of serving them
+	if flag.NArg() > 0 && !isCommand(flag.Arg(0)) {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+		printUsage()
+		os.Exit(2)
+	}
+
+	// Without a config file, look for one next to the executable and fall
+	// back to the built-in defaults when there is none
+	if *configPath == "" {
+		execPath, err := os.Executable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting executable path: %v\n", err)
+			os.Exit(1)
+		}
+		if path := filepath.Join(filepath.Dir(execPath), "config.json"); fileExists(path) {
+			*configPath = path
+		}
+	}
+
+	// Load configuration
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		fmt.Printf("Error getting executable path: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
-	execDir := filepath.Dir(execPath)
-	configPath := filepath.Join(execDir, "config.json")
 
-	// Load configuration
-	cfg, err := config.Load(configPath)
+	// Commands only log errors unless a level is configured, since they
+	// print failed calls themselves
+	if flag.NArg() > 0 && cfg.Log.Level == "" {
+		cfg.Log.Level = "error"
+	}
+
+	// Log to standard error or the configured file; standard output is
+	// reserved for the stdio transport and command results
+	logger, closeLog, err := logging.New(cfg.Log)
 	if err != nil {
-		fmt.Printf("Error loading configuration: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error opening log: %v\n", err)
 		os.Exit(1)
 	}
+	defer closeLog()
 
 	// Create and start the MCP server
-	server := api.NewServer(cfg)
-	if err := server.Start(); err != nil {
-		fmt.Printf("Server error: %v\n", err)
+	server, err := api.NewServer(cfg, logger)
+	if err != nil {
+		logger.Error("error creating server", "error", err)
+		closeLog()
 		os.Exit(1)
 	}
+	slog.SetDefault(server.Logger())
+
+	// SIGINT and SIGTERM shut down gracefully; a second signal exits at once
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if flag.NArg() > 0 {
+		code := runCommand(ctx, server, flag.Arg(0), flag.Args()[1:], os.Stdout, os.Stderr)
+		if closeErr := server.Close(); closeErr != nil {
+			logger.Error("error closing server", "error", closeErr)
+		}
+		closeLog()
+		os.Exit(code)
+	}
+
+	logger.Info("starting server", "transport", *transport)
+	err = server.Start(ctx, *transport)
+	if closeErr := server.Close(); closeErr != nil {
+		logger.Error("error closing server", "error", closeErr)
+	}
+	if err != nil {
+		logger.Error("server error", "error", err)
+		closeLog()
+		os.Exit(1)
+	}
+	logger.Info("server stopped")
+}
+
+// fileExists reports whether a regular file exists at the path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
 }
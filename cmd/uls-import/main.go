@@ -0,0 +1,27 @@
+// Command uls-import converts the weekly FCC ULS amateur license archive
+// (l_amat.zip) into the local store used by the "uls" callsign provider
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pleska/ham-radio-assistant/internal/uls"
+)
+
+func main() {
+	archivePath := flag.String("archive", "l_amat.zip", "path to the FCC ULS amateur license archive")
+	storePath := flag.String("out", "uls.db", "path of the store file to write")
+	zipCentroids := flag.String("zip-centroids", "", "optional US Census ZCTA gazetteer file used to locate licensees by ZIP code")
+	flag.Parse()
+
+	stats, err := uls.Import(*archivePath, *storePath, *zipCentroids)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d active licenses into %s (%d located by ZIP code, %d inactive skipped)\n",
+		stats.Licenses, stats.StorePath, stats.Geocoded, stats.Skipped)
+}
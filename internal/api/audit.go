@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// audit is tool handler middleware that writes an audit record for every
+// tool call: the tool and its arguments, how long it took, the upstream
+// requests it made and any error. Calls that fail are logged as warnings.
+func (s *Server) audit(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, trace := upstream.WithTrace(ctx)
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		attrs := []slog.Attr{
+			slog.String("tool", request.Params.Name),
+			slog.Any("arguments", request.GetArguments()),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if requests := trace.Requests(); len(requests) > 0 {
+			attrs = append(attrs, slog.Any("upstream", upstreamSummary(requests)))
+		}
+
+		level := slog.LevelInfo
+		if message := callError(result, err); message != "" {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("error", message))
+		}
+		s.logger.LogAttrs(ctx, level, "tool call", attrs...)
+
+		return result, err
+	}
+}
+
+// upstreamSummary describes each upstream request attempt as host and
+// status, or host and error
+func upstreamSummary(requests []upstream.Request) []string {
+	summary := make([]string, len(requests))
+	for i, r := range requests {
+		if r.Error != "" {
+			summary[i] = fmt.Sprintf("%s: %s", r.Host, r.Error)
+		} else {
+			summary[i] = fmt.Sprintf("%s: %d", r.Host, r.Status)
+		}
+	}
+	return summary
+}
+
+// callError returns the error of a tool call, whether returned by the
+// handler or reported in the result, or an empty string
+func callError(result *mcp.CallToolResult, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if result == nil || !result.IsError {
+		return ""
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "tool returned an error"
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/cache"
+	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// requestsPerLoad is the most upstream requests one cached load makes, such
+// as a QRZ login before the lookup
+const requestsPerLoad = 2
+
+// newCache builds the upstream response cache from the configuration. A
+// load shared by concurrent calls may take as long as its upstream requests
+// can with every retry.
+func newCache(cfg config.CacheConfig, client *upstream.Client) (*cache.Cache, error) {
+	ttls := make(map[string]time.Duration, len(config.DefaultCacheTTLs))
+	for source, ttl := range config.DefaultCacheTTLs {
+		ttls[source] = ttl
+	}
+
+	for source, value := range cfg.TTLs {
+		if _, ok := config.DefaultCacheTTLs[source]; !ok {
+			return nil, fmt.Errorf("unknown cache source %q", source)
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TTL for %s: %v", source, err)
+		}
+		ttls[source] = ttl
+	}
+
+	return cache.New(ttls, cfg.Path, requestsPerLoad*client.MaxDuration())
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/pota"
+)
+
+// Defaults of the spot history limits
+const (
+	defaultSpotRetention = 30 * 24 * time.Hour
+	defaultMaxSpots      = 100000
+)
+
+// newSpotHistory opens the spot history and returns it with the poll
+// interval, or nil when no history is configured
+func newSpotHistory(cfg config.SpotHistoryConfig) (*pota.History, time.Duration, error) {
+	if cfg.Path == "" {
+		return nil, 0, nil
+	}
+
+	interval := pota.DefaultPollInterval
+	if cfg.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(cfg.Interval); err != nil || interval <= 0 {
+			return nil, 0, fmt.Errorf("invalid poll interval %q", cfg.Interval)
+		}
+	}
+	retention := defaultSpotRetention
+	if cfg.Retention != "" {
+		var err error
+		if retention, err = time.ParseDuration(cfg.Retention); err != nil {
+			return nil, 0, fmt.Errorf("invalid retention: %v", err)
+		}
+	}
+	maxSpots := defaultMaxSpots
+	if cfg.MaxSpots != nil {
+		maxSpots = *cfg.MaxSpots
+	}
+
+	history, err := pota.OpenHistory(cfg.Path, retention, maxSpots)
+	if err != nil {
+		return nil, 0, err
+	}
+	return history, interval, nil
+}
+
+// pollSpots adds the current spots to the history in the background, when
+// one is configured, and returns a function that stops polling and waits
+// for a poll in progress to finish
+func (s *Server) pollSpots() func() {
+	if s.history == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pota.Poll(ctx, s.pota, s.history, s.pollInterval)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
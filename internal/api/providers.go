@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/uls"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// newCallsignProvider builds the callsign provider chain from the configuration.
+// It also returns the ULS store the chain reads, which the caller must
+// close, or nil when the uls provider is not configured.
+func newCallsignProvider(cfg config.CallsignConfig, client *upstream.Client, baseURLs map[string]string) (lookup.CallsignProvider, *uls.Store, error) {
+	var providers []lookup.CallsignProvider
+	var store *uls.Store
+	// fail closes the store opened so far before returning an error
+	fail := func(err error) (lookup.CallsignProvider, *uls.Store, error) {
+		if store != nil {
+			store.Close()
+		}
+		return nil, nil, err
+	}
+
+	for _, name := range cfg.Providers {
+		switch strings.ToLower(name) {
+		case "callook":
+			providers = append(providers, lookup.NewCallook(client, baseURLs["callook"]))
+		case "uls":
+			if cfg.ULS.Path == "" {
+				return fail(fmt.Errorf("callsign provider uls requires a store path"))
+			}
+			if store == nil {
+				var err error
+				if store, err = uls.Open(cfg.ULS.Path); err != nil {
+					return fail(err)
+				}
+			}
+			providers = append(providers, lookup.NewULS(store))
+		case "hamdb":
+			providers = append(providers, lookup.NewHamDB(client, baseURLs["hamdb"]))
+		case "qrz":
+			if cfg.QRZ.Username == "" || cfg.QRZ.Password == "" {
+				return fail(fmt.Errorf("callsign provider qrz requires a username and password"))
+			}
+			providers = append(providers, lookup.NewQRZ(client, baseURLs["qrz"], cfg.QRZ.Username, cfg.QRZ.Password))
+		case "hamqth":
+			if cfg.HamQTH.Username == "" || cfg.HamQTH.Password == "" {
+				return fail(fmt.Errorf("callsign provider hamqth requires a username and password"))
+			}
+			providers = append(providers, lookup.NewHamQTH(client, baseURLs["hamqth"], cfg.HamQTH.Username, cfg.HamQTH.Password))
+		default:
+			return fail(fmt.Errorf("unknown callsign provider %q", name))
+		}
+	}
+
+	return lookup.NewChain(providers...), store, nil
+}
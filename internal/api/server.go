@@ -1,52 +1,303 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/cache"
 	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/logging"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/metrics"
+	"github.com/pleska/ham-radio-assistant/internal/pota"
 	"github.com/pleska/ham-radio-assistant/internal/tools"
+	"github.com/pleska/ham-radio-assistant/internal/uls"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// httpCloseTimeout is how long open HTTP connections are given to close at
+// shutdown, after the tool calls have been drained
+const httpCloseTimeout = time.Second
+
+// Supported transports for serving the MCP protocol
+const (
+	TransportStdio = "stdio"
+	TransportSSE   = "sse"
+	TransportHTTP  = "http"
 )
 
 // Server represents the MCP API server
 type Server struct {
 	config    *config.Config
 	mcpServer *server.MCPServer
+	callsigns lookup.CallsignProvider
+	uls       *uls.Store
+	entities  *dxcc.Database
+	parks     *pota.Catalog
+	history   *pota.History
+	station   *tools.Station
+	upstream  *upstream.Client
+	pota      *pota.Client
+	cache     *cache.Cache
+	logger    *slog.Logger
+	metrics   *metrics.Metrics
+
+	// pollInterval is how often spots are added to the history
+	pollInterval time.Duration
+
+	// calls tracks in-flight tool calls for a graceful shutdown
+	calls         callTracker
+	shutdownGrace time.Duration
+
+	// registerOnce registers the tools once, whether they are served or called in-process
+	registerOnce sync.Once
 }
 
-// NewServer creates a new MCP server instance
-func NewServer(cfg *config.Config) *Server {
-	mcpServer := server.NewMCPServer(
+// NewServer creates a new MCP server instance. Records written to the
+// logger are also sent to the MCP client whose request they were logged for.
+func NewServer(cfg *config.Config, logger *slog.Logger) (*Server, error) {
+	collected := metrics.New()
+
+	client, err := newUpstreamClient(cfg.Upstream, collected.ObserveUpstream)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream configuration: %w", err)
+	}
+
+	responses, err := newCache(cfg.Cache, client)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache configuration: %w", err)
+	}
+	collected.RegisterCache(responses)
+
+	callsigns, store, err := newCallsignProvider(cfg.Callsign, client, cfg.Upstream.BaseURLs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid callsign configuration: %w", err)
+	}
+	callsigns = lookup.NewCached(callsigns, responses)
+
+	// The DXCC prefix table is optional
+	var entities *dxcc.Database
+	if cfg.DXCC.Path != "" {
+		if entities, err = dxcc.Load(cfg.DXCC.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	// The POTA park list is optional
+	var parks *pota.Catalog
+	if cfg.POTA.ParksPath != "" {
+		if parks, err = pota.LoadCatalog(cfg.POTA.ParksPath); err != nil {
+			return nil, err
+		}
+	}
+
+	// The spot history is optional
+	history, pollInterval, err := newSpotHistory(cfg.POTA.History)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spot history configuration: %w", err)
+	}
+
+	station, err := tools.NewStation(cfg.Station, callsigns, entities)
+	if err != nil {
+		return nil, fmt.Errorf("invalid station configuration: %w", err)
+	}
+
+	shutdownGrace := DefaultShutdownGrace
+	if cfg.Server.ShutdownGrace != "" {
+		if shutdownGrace, err = time.ParseDuration(cfg.Server.ShutdownGrace); err != nil {
+			return nil, fmt.Errorf("invalid shutdown grace period: %v", err)
+		}
+	}
+
+	s := &Server{
+		config:    cfg,
+		callsigns: callsigns,
+		uls:       store,
+		entities:  entities,
+		parks:     parks,
+		history:   history,
+		station:   station,
+		upstream:  client,
+		pota:      pota.NewClient(client, cfg.Upstream.BaseURLs["pota"], responses),
+		cache:     responses,
+		metrics:   collected,
+
+		pollInterval:  pollInterval,
+		shutdownGrace: shutdownGrace,
+	}
+	s.mcpServer = server.NewMCPServer(
 		"Ham Radio Assistant",
 		"1.1.0",
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(s.audit),
+		server.WithToolHandlerMiddleware(collected.Middleware),
+		server.WithToolHandlerMiddleware(s.track),
 	)
+	s.logger = slog.New(logging.NewMCPHandler(logger.Handler(), s.mcpServer))
 
-	return &Server{
-		config:    cfg,
-		mcpServer: mcpServer,
-	}
+	return s, nil
+}
+
+// Logger returns the server's logger, which also sends records to MCP clients
+func (s *Server) Logger() *slog.Logger {
+	return s.logger
 }
 
-// RegisterTools registers all the tools with the MCP server
+// RegisterTools registers the tools enabled in the configuration with the MCP server
 func (s *Server) RegisterTools() {
+	registrar := &toolRegistrar{mcpServer: s.mcpServer, tools: s.config.Tools}
+	limit := func(name string) int { return s.config.Tools[name].Limit }
+
 	// Register the callsign lookup tool
-	tools.RegisterCallsignLookupTool(s.mcpServer)
-	tools.RegisterAntennaBearingTool(s.mcpServer)
-	tools.RegisterCallsignBearingTool(s.mcpServer)
-	tools.RegisterPotaParkLookupTool(s.mcpServer)
-	tools.RegisterPotaSpotsTool(s.mcpServer)
+	tools.RegisterCallsignLookupTool(registrar, s.callsigns)
+	tools.RegisterAntennaBearingTool(registrar, s.station)
+	tools.RegisterGridDistanceTool(registrar, s.station)
+	tools.RegisterPathProfileTool(registrar, s.station, limit("path-profile"))
+	tools.RegisterCallsignBearingTool(registrar, s.callsigns, s.entities, s.station)
+	tools.RegisterBearingMapTool(registrar, s.callsigns, s.entities, s.station, limit("bearing-map"))
+	tools.RegisterSunTimesTool(registrar, s.callsigns, s.entities, s.station)
+	tools.RegisterBandInfoTool(registrar, s.station)
+	tools.RegisterPotaParkLookupTool(registrar, s.potaClient("pota-park-lookup"), s.parks)
+	tools.RegisterPotaSpotsTool(registrar, s.potaClient("pota-spots"), s.station, limit("pota-spots"))
+	tools.RegisterCacheTools(registrar, s.cache)
+
+	// Tools that depend on optional data files
+	if s.entities != nil {
+		tools.RegisterCallsignEntityTool(registrar, s.entities)
+	}
+	if s.parks != nil {
+		tools.RegisterPotaParkSearchTool(registrar, s.parks, limit("pota-park-search"))
+		tools.RegisterPotaParksNearbyTool(registrar, s.parks, s.callsigns, s.entities, s.station, limit("pota-parks-nearby"))
+	}
+	if s.history != nil {
+		tools.RegisterPotaSpotHistoryTool(registrar, s.history, s.station, limit("pota-spot-history"))
+	}
 
 	// Additional tools can be registered here in the future
 }
 
-// Start starts the MCP server
-func (s *Server) Start() error {
-	s.RegisterTools()
+// potaClient returns the POTA client for a tool, with the tool's own base
+// URL or cache TTL when one is configured. A tool with its own base URL
+// does not share cached responses with the other tools.
+func (s *Server) potaClient(name string) *pota.Client {
+	cfg := s.config.Tools[name]
+	ttl, _ := toolCacheTTL(cfg)
+	switch {
+	case cfg.BaseURL != "", ttl == 0:
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = s.config.Upstream.BaseURLs["pota"]
+		}
+		return pota.NewClient(s.upstream, baseURL, nil)
+	case ttl > 0:
+		return s.pota.WithMaxAge(ttl)
+	default:
+		return s.pota
+	}
+}
+
+// Start serves the MCP protocol using the given transport until the context
+// is done or the transport fails. When the context is done new tool calls
+// are refused, running calls are given the configured grace period to
+// finish before they are canceled, and Start returns nil. Spots are polled
+// into the history, when one is configured, while the server runs.
+func (s *Server) Start(ctx context.Context, transport string) error {
+	s.registerOnce.Do(s.RegisterTools)
+
+	stopPolling := s.pollSpots()
+	defer stopPolling()
+
+	switch transport {
+	case TransportStdio:
+		return s.serveStdio(ctx)
+	case TransportSSE:
+		// The SSE server handles both the /sse and /message endpoints
+		mux := http.NewServeMux()
+		mux.Handle("/", server.NewSSEServer(s.mcpServer))
+		return s.serveHTTP(ctx, mux)
+	case TransportHTTP:
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", server.NewStreamableHTTPServer(s.mcpServer))
+		return s.serveHTTP(ctx, mux)
+	default:
+		return fmt.Errorf("unknown transport %q (expected %s, %s or %s)",
+			transport, TransportStdio, TransportSSE, TransportHTTP)
+	}
+}
+
+// Close releases the server's resources, writing the cache to disk when it
+// is persisted and the spot history when one is configured, and closing
+// the ULS store
+func (s *Server) Close() error {
+	return errors.Join(s.history.Close(), s.cache.Close(), s.uls.Close())
+}
+
+// serveStdio serves the MCP protocol over standard input and output until
+// the input ends or the context is done
+func (s *Server) serveStdio(ctx context.Context) error {
+	// Tool calls run in the context of the stdio server, which is only
+	// canceled once the running calls are drained
+	serveCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.drain()
+			stop()
+		case <-serveCtx.Done():
+		}
+	}()
 
-	// Start the stdio server
-	if err := server.ServeStdio(s.mcpServer); err != nil {
+	stdio := server.NewStdioServer(s.mcpServer)
+	stdio.SetErrorLogger(slog.NewLogLogger(s.logger.Handler(), slog.LevelError))
+	if err := stdio.Listen(serveCtx, os.Stdin, os.Stdout); err != nil && ctx.Err() == nil {
 		return fmt.Errorf("server error: %w", err)
 	}
+	return nil
+}
+
+// serveHTTP listens on the configured server port and serves the MCP
+// endpoints on the mux, along with the metrics when they are enabled,
+// until the context is done
+func (s *Server) serveHTTP(ctx context.Context, mux *http.ServeMux) error {
+	if path := s.config.Server.MetricsPath; path != "" {
+		mux.Handle(path, s.metrics.Handler())
+	}
+
+	httpServer := &http.Server{
+		Addr:     fmt.Sprintf(":%d", s.config.Server.Port),
+		Handler:  mux,
+		ErrorLog: slog.NewLogLogger(s.logger.Handler(), slog.LevelError),
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.ListenAndServe() }()
 
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server error: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.drain()
+
+	// Streams to connected clients stay open until they are closed, so
+	// close whatever is left once the calls are done
+	closeCtx, cancel := context.WithTimeout(context.Background(), httpCloseTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(closeCtx); err != nil {
+		httpServer.Close()
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultShutdownGrace is how long in-flight tool calls may run after a
+// shutdown begins before they are canceled, unless configured
+const DefaultShutdownGrace = 5 * time.Second
+
+// errShuttingDown is returned for tool calls made once a shutdown has begun
+var errShuttingDown = errors.New("server is shutting down")
+
+// callTracker keeps track of in-flight tool calls, so that a shutdown can
+// refuse new calls, wait for running ones and cancel those still running
+// after the grace period
+type callTracker struct {
+	mu      sync.Mutex
+	closed  bool
+	next    int
+	cancels map[int]context.CancelFunc
+	running sync.WaitGroup
+}
+
+// start registers a call and returns its context, which is canceled if the
+// call is still running when the grace period ends, and a function to call
+// when it returns. It reports false once a shutdown has begun.
+func (t *callTracker) start(ctx context.Context) (context.Context, func(), bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return ctx, nil, false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	if t.cancels == nil {
+		t.cancels = make(map[int]context.CancelFunc)
+	}
+	id := t.next
+	t.next++
+	t.cancels[id] = cancel
+	t.running.Add(1)
+
+	return ctx, func() {
+		t.mu.Lock()
+		delete(t.cancels, id)
+		t.mu.Unlock()
+		cancel()
+		t.running.Done()
+	}, true
+}
+
+// count returns the number of running calls
+func (t *callTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.cancels)
+}
+
+// shutdown refuses new calls and waits for running calls to return,
+// canceling them once the grace period is over
+func (t *callTracker) shutdown(grace time.Duration) {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.running.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return
+	case <-timer.C:
+	}
+
+	t.mu.Lock()
+	for _, cancel := range t.cancels {
+		cancel()
+	}
+	t.mu.Unlock()
+	<-done
+}
+
+// track is tool handler middleware that refuses calls during a shutdown
+// and lets the shutdown cancel calls that outlast the grace period
+func (s *Server) track(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, done, ok := s.calls.start(ctx)
+		if !ok {
+			return nil, errShuttingDown
+		}
+		defer done()
+		return next(ctx, request)
+	}
+}
+
+// drain stops accepting tool calls and waits for the running ones, for at
+// most the configured grace period before canceling them
+func (s *Server) drain() {
+	running := s.calls.count()
+	s.logger.Info("shutting down", "running_calls", running, "grace", s.shutdownGrace)
+	s.calls.shutdown(s.shutdownGrace)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/config"
+)
+
+// toolCacheTTL parses the cache TTL of a tool. It returns a negative
+// duration when none is configured.
+func toolCacheTTL(cfg config.ToolConfig) (time.Duration, error) {
+	if cfg.CacheTTL == "" {
+		return -1, nil
+	}
+	ttl, err := time.ParseDuration(cfg.CacheTTL)
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return ttl, nil
+}
+
+// toolRegistrar adds tools to the MCP server, leaving out disabled tools
+// and replacing descriptions as configured
+type toolRegistrar struct {
+	mcpServer *server.MCPServer
+	tools     map[string]config.ToolConfig
+}
+
+// AddTool adds the tool unless it is disabled
+func (r *toolRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	cfg := r.tools[tool.Name]
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return
+	}
+	if cfg.Description != "" {
+		tool.Description = cfg.Description
+	}
+	r.mcpServer.AddTool(tool, handler)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// newUpstreamClient builds the shared upstream HTTP client from the
+// configuration. Every request attempt is passed to observe.
+func newUpstreamClient(cfg config.UpstreamConfig, observe func(upstream.Request)) (*upstream.Client, error) {
+	opts := upstream.Options{Observe: observe}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %v", err)
+		}
+		opts.Timeout = timeout
+	}
+
+	opts.HostTimeouts = make(map[string]time.Duration, len(cfg.HostTimeouts))
+	for host, value := range cfg.HostTimeouts {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for host %s: %v", host, err)
+		}
+		opts.HostTimeouts[host] = timeout
+	}
+
+	if cfg.Retries != nil {
+		if *cfg.Retries < 0 {
+			return nil, fmt.Errorf("retries must not be negative")
+		}
+		// Zero in the options selects the default, so disable retries explicitly
+		opts.Retries = *cfg.Retries
+		if opts.Retries == 0 {
+			opts.Retries = -1
+		}
+	}
+
+	for name, baseURL := range cfg.BaseURLs {
+		if !slices.Contains(config.Services, name) {
+			return nil, fmt.Errorf("unknown service %q in base_urls", name)
+		}
+		if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid base URL for %s: %q", name, baseURL)
+		}
+	}
+
+	return upstream.New(opts), nil
+}
@@ -0,0 +1,267 @@
+// Package azmap renders azimuthal equidistant maps centered on a station.
+// On such a map every straight line through the center is a great circle,
+// so the direction to any point is its antenna bearing and the distance from
+// the center is proportional to the great-circle distance.
+package azmap
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// halfCircumferenceKm is the distance from the center to the edge of the
+// map, the antipode of the center on a spherical Earth
+const halfCircumferenceKm = math.Pi * 6371.0
+
+// Defaults applied to zero Options fields
+const (
+	DefaultSize   = 800
+	DefaultRingKm = 5000
+)
+
+// Size limits for rendered maps in pixels
+const (
+	MinSize = 200
+	MaxSize = 4000
+)
+
+// margin is the space in pixels around the map disk for compass labels
+const margin = 40
+
+// Map colors
+var (
+	colorBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorOcean      = color.RGBA{0xe8, 0xf1, 0xfa, 0xff}
+	colorCoast      = color.RGBA{0x3a, 0x6b, 0x35, 0xff}
+	colorGrid       = color.RGBA{0xb0, 0xb8, 0xc4, 0xff}
+	colorText       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	colorPath       = color.RGBA{0xd0, 0x21, 0x2a, 0xff}
+	colorCenter     = color.RGBA{0x1f, 0x4e, 0x9a, 0xff}
+)
+
+// Marker is a labelled point on the map
+type Marker struct {
+	Label     string
+	Latitude  float64
+	Longitude float64
+}
+
+// Options describes a map to render
+type Options struct {
+	// Center is the station the map is centered on
+	Center Marker
+	// Destinations are plotted with a great-circle path from the center
+	Destinations []Marker
+	// Size is the width and height of the map in pixels
+	Size int
+	// RingKm is the spacing of the distance rings in kilometers
+	RingKm float64
+}
+
+//go:embed data/coastline.json
+var coastlineJSON []byte
+
+// coastline is one outline from the embedded dataset, with points given
+// as [longitude, latitude] pairs
+type coastline struct {
+	Name   string       `json:"name"`
+	Points [][2]float64 `json:"points"`
+}
+
+// loadCoastlines parses the embedded coastline dataset once
+var loadCoastlines = sync.OnceValues(func() ([]coastline, error) {
+	var coastlines []coastline
+	if err := json.Unmarshal(coastlineJSON, &coastlines); err != nil {
+		return nil, fmt.Errorf("invalid coastline data: %v", err)
+	}
+	return coastlines, nil
+})
+
+// point is a position on the canvas in pixels
+type point struct {
+	X, Y float64
+}
+
+// canvas is the drawing surface a map is rendered onto
+type canvas interface {
+	fillCircle(center point, radius float64, fill color.RGBA)
+	strokeCircle(center point, radius, width float64, stroke color.RGBA)
+	polyline(points []point, width float64, stroke color.RGBA)
+	// text draws a single line of text centered on the given point
+	text(at point, s string, fill color.RGBA, bold bool)
+}
+
+// projection maps latitude and longitude to canvas pixels
+type projection struct {
+	lon0    float64
+	sinLat0 float64
+	cosLat0 float64
+	center  point
+	radius  float64
+}
+
+func newProjection(lat, lon float64, center point, radius float64) *projection {
+	return &projection{
+		lon0:    lon,
+		sinLat0: math.Sin(lat * math.Pi / 180),
+		cosLat0: math.Cos(lat * math.Pi / 180),
+		center:  center,
+		radius:  radius,
+	}
+}
+
+// project returns the canvas position of a point
+func (p *projection) project(lat, lon float64) point {
+	phi := lat * math.Pi / 180
+	dLon := (lon - p.lon0) * math.Pi / 180
+
+	cosC := p.sinLat0*math.Sin(phi) + p.cosLat0*math.Cos(phi)*math.Cos(dLon)
+	c := math.Acos(math.Max(-1, math.Min(1, cosC)))
+	theta := math.Atan2(math.Sin(dLon)*math.Cos(phi),
+		p.cosLat0*math.Sin(phi)-p.sinLat0*math.Cos(phi)*math.Cos(dLon))
+
+	return p.polar(theta, p.radius*c/math.Pi)
+}
+
+// polar returns the canvas position at the given bearing in radians and
+// distance in pixels from the center
+func (p *projection) polar(theta, rho float64) point {
+	return point{X: p.center.X + rho*math.Sin(theta), Y: p.center.Y - rho*math.Cos(theta)}
+}
+
+// validate checks the options and fills in defaults
+func (o *Options) validate() error {
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	if o.Size < MinSize || o.Size > MaxSize {
+		return fmt.Errorf("size must be between %d and %d pixels", MinSize, MaxSize)
+	}
+	if o.RingKm == 0 {
+		o.RingKm = DefaultRingKm
+	}
+	if o.RingKm < 0 {
+		return errors.New("ring spacing must be positive")
+	}
+	markers := append([]Marker{o.Center}, o.Destinations...)
+	for _, m := range markers {
+		if m.Latitude < -90 || m.Latitude > 90 || m.Longitude < -180 || m.Longitude > 180 {
+			return fmt.Errorf("coordinates of %q are out of range", m.Label)
+		}
+	}
+	return nil
+}
+
+// render draws the map onto a canvas of opts.Size pixels square
+func render(c canvas, opts Options) error {
+	coastlines, err := loadCoastlines()
+	if err != nil {
+		return err
+	}
+
+	size := float64(opts.Size)
+	center := point{X: size / 2, Y: size / 2}
+	radius := size/2 - margin
+	proj := newProjection(opts.Center.Latitude, opts.Center.Longitude, center, radius)
+
+	// The whole Earth, with the antipode of the center on the edge
+	c.fillCircle(center, radius, colorOcean)
+
+	// Coastlines are only stroked: filling them is ambiguous once the
+	// projection has split an outline around the antipode
+	for _, coast := range coastlines {
+		for _, part := range projectOutline(proj, coast.Points) {
+			c.polyline(part, 1, colorCoast)
+		}
+	}
+
+	// Distance rings, labelled between the north and 30 degree lines. A ring
+	// that would almost touch the edge of the map is left out.
+	for km := opts.RingKm; km < halfCircumferenceKm-opts.RingKm/4; km += opts.RingKm {
+		rho := radius * km / halfCircumferenceKm
+		c.strokeCircle(center, rho, 0.75, colorGrid)
+		c.text(proj.polar(15*math.Pi/180, rho), fmt.Sprintf("%.0f km", km), colorText, false)
+	}
+
+	// Compass rose
+	for bearing := 0; bearing < 360; bearing += 30 {
+		theta := float64(bearing) * math.Pi / 180
+		c.polyline([]point{center, proj.polar(theta, radius)}, 0.75, colorGrid)
+
+		label, bold := strconv.Itoa(bearing), false
+		switch bearing {
+		case 0:
+			label, bold = "N", true
+		case 90:
+			label, bold = "E", true
+		case 180:
+			label, bold = "S", true
+		case 270:
+			label, bold = "W", true
+		}
+		c.text(proj.polar(theta, radius+16), label, colorText, bold)
+	}
+	c.strokeCircle(center, radius, 1.5, colorText)
+
+	// Great-circle paths are straight lines from the center
+	for _, dest := range opts.Destinations {
+		at := proj.project(dest.Latitude, dest.Longitude)
+		c.polyline([]point{center, at}, 2, colorPath)
+	}
+	for _, dest := range opts.Destinations {
+		at := proj.project(dest.Latitude, dest.Longitude)
+		c.fillCircle(at, 4, colorPath)
+		c.text(point{X: at.X, Y: at.Y - 12}, dest.Label, colorText, true)
+	}
+
+	c.fillCircle(center, 5, colorCenter)
+	c.text(point{X: center.X, Y: center.Y + 14}, opts.Center.Label, colorCenter, true)
+
+	return nil
+}
+
+// projectOutline projects a coastline, densifying long edges so they curve
+// correctly and splitting the outline wherever it jumps across the map near
+// the antipode
+func projectOutline(proj *projection, points [][2]float64) [][]point {
+	const maxStepDeg = 2
+	maxJump := proj.radius / 6
+
+	var parts [][]point
+	var current []point
+	add := func(lon, lat float64) {
+		p := proj.project(lat, lon)
+		if n := len(current); n > 0 && math.Hypot(p.X-current[n-1].X, p.Y-current[n-1].Y) > maxJump {
+			if n > 1 {
+				parts = append(parts, current)
+			}
+			current = nil
+		}
+		current = append(current, p)
+	}
+
+	for i, pt := range points {
+		if i == 0 {
+			add(pt[0], pt[1])
+			continue
+		}
+		prev := points[i-1]
+		dLon := math.Mod(pt[0]-prev[0]+540, 360) - 180
+		dLat := pt[1] - prev[1]
+		steps := int(math.Ceil(math.Max(math.Abs(dLon), math.Abs(dLat)) / maxStepDeg))
+		for s := 1; s <= steps; s++ {
+			t := float64(s) / float64(steps)
+			add(prev[0]+dLon*t, prev[1]+dLat*t)
+		}
+	}
+	if len(current) > 1 {
+		parts = append(parts, current)
+	}
+	return parts
+}
@@ -0,0 +1,119 @@
+package azmap
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// circleSegments is the number of edges used to approximate a circle
+const circleSegments = 180
+
+// RenderPNG renders the map as a PNG image
+func RenderPNG(opts Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Size, opts.Size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+	if err := render(&pngCanvas{img: img}, opts); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pngCanvas draws onto an RGBA image with an anti-aliasing rasterizer
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+// fill rasterizes the path built by the given function in one color
+func (c *pngCanvas) fill(fill color.RGBA, path func(z *vector.Rasterizer)) {
+	bounds := c.img.Bounds()
+	z := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+	path(z)
+	z.Draw(c.img, bounds, image.NewUniform(fill), image.Point{})
+}
+
+func (c *pngCanvas) fillCircle(center point, radius float64, fill color.RGBA) {
+	c.fill(fill, func(z *vector.Rasterizer) {
+		for i, p := range circlePoints(center, radius) {
+			if i == 0 {
+				z.MoveTo(float32(p.X), float32(p.Y))
+			} else {
+				z.LineTo(float32(p.X), float32(p.Y))
+			}
+		}
+		z.ClosePath()
+	})
+}
+
+func (c *pngCanvas) strokeCircle(center point, radius, width float64, stroke color.RGBA) {
+	points := circlePoints(center, radius)
+	c.polyline(append(points, points[0]), width, stroke)
+}
+
+// polyline strokes each segment as a quad. Every quad winds the same way,
+// so overlaps at the joins saturate rather than cancel out.
+func (c *pngCanvas) polyline(points []point, width float64, stroke color.RGBA) {
+	c.fill(stroke, func(z *vector.Rasterizer) {
+		for i := 1; i < len(points); i++ {
+			a, b := points[i-1], points[i]
+			length := math.Hypot(b.X-a.X, b.Y-a.Y)
+			if length == 0 {
+				continue
+			}
+			nx := -(b.Y - a.Y) / length * width / 2
+			ny := (b.X - a.X) / length * width / 2
+			z.MoveTo(float32(a.X+nx), float32(a.Y+ny))
+			z.LineTo(float32(b.X+nx), float32(b.Y+ny))
+			z.LineTo(float32(b.X-nx), float32(b.Y-ny))
+			z.LineTo(float32(a.X-nx), float32(a.Y-ny))
+			z.ClosePath()
+		}
+	})
+}
+
+func (c *pngCanvas) text(at point, s string, fill color.RGBA, bold bool) {
+	face := basicfont.Face7x13
+	width := utf8.RuneCountInString(s) * face.Advance
+	d := font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(fill),
+		Face: face,
+	}
+	x := int(math.Round(at.X)) - width/2
+	y := int(math.Round(at.Y)) + (face.Ascent-face.Descent)/2
+
+	d.Dot = fixed.P(x, y)
+	d.DrawString(s)
+	if bold {
+		d.Dot = fixed.P(x+1, y)
+		d.DrawString(s)
+	}
+}
+
+// circlePoints approximates a circle as a polygon
+func circlePoints(center point, radius float64) []point {
+	points := make([]point, circleSegments)
+	for i := range points {
+		theta := 2 * math.Pi * float64(i) / circleSegments
+		points[i] = point{X: center.X + radius*math.Cos(theta), Y: center.Y + radius*math.Sin(theta)}
+	}
+	return points
+}
@@ -0,0 +1,69 @@
+package azmap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// RenderSVG renders the map as an SVG document
+func RenderSVG(opts Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		opts.Size, opts.Size, opts.Size, opts.Size)
+	fmt.Fprintf(&c.b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(colorBackground))
+	if err := render(c, opts); err != nil {
+		return nil, err
+	}
+	c.b.WriteString("</svg>\n")
+
+	return []byte(c.b.String()), nil
+}
+
+// svgCanvas draws onto an SVG document
+type svgCanvas struct {
+	b strings.Builder
+}
+
+func (c *svgCanvas) fillCircle(center point, radius float64, fill color.RGBA) {
+	fmt.Fprintf(&c.b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`+"\n",
+		center.X, center.Y, radius, svgColor(fill))
+}
+
+func (c *svgCanvas) strokeCircle(center point, radius, width float64, stroke color.RGBA) {
+	fmt.Fprintf(&c.b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s" stroke-width="%g"/>`+"\n",
+		center.X, center.Y, radius, svgColor(stroke), width)
+}
+
+func (c *svgCanvas) polyline(points []point, width float64, stroke color.RGBA) {
+	c.b.WriteString(`<polyline points="`)
+	for i, p := range points {
+		if i > 0 {
+			c.b.WriteByte(' ')
+		}
+		fmt.Fprintf(&c.b, "%.1f,%.1f", p.X, p.Y)
+	}
+	fmt.Fprintf(&c.b, `" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"/>`+"\n",
+		svgColor(stroke), width)
+}
+
+func (c *svgCanvas) text(at point, s string, fill color.RGBA, bold bool) {
+	weight := "normal"
+	if bold {
+		weight = "bold"
+	}
+	fmt.Fprintf(&c.b, `<text x="%.1f" y="%.1f" fill="%s" font-weight="%s" text-anchor="middle" dominant-baseline="central">`,
+		at.X, at.Y, svgColor(fill), weight)
+	xml.EscapeText(&c.b, []byte(s))
+	c.b.WriteString("</text>\n")
+}
+
+// svgColor formats a color as a hex triplet
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
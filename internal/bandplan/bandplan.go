@@ -0,0 +1,154 @@
+// Package bandplan describes the amateur bands from 160m to 23cm as laid out
+// in the IARU Region 1, 2 and 3 band plans: the band edges, the segments
+// set aside for CW, data and phone, and the conventional dial frequencies of
+// the popular digital modes. The plans are simplified to the segments an
+// operator tunes by and are a guide, not a statement of any country's
+// regulations.
+package bandplan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRegion is used when no region is known. Its bands are the widest
+// of the three regions, so every band of the other regions falls within them.
+const DefaultRegion = 2
+
+// Uses of a band segment
+const (
+	UseCW        = "CW"
+	UseData      = "Data"
+	UsePhone     = "Phone"
+	UseAllModes  = "All modes"
+	UseBeacons   = "Beacons"
+	UseSatellite = "Satellite"
+	UseFM        = "FM"
+)
+
+// useModes describes the modes expected in a segment of each use
+var useModes = map[string]string{
+	UseCW:        "CW",
+	UseData:      "narrow band digital modes such as FT8, FT4, JS8, PSK31 and RTTY, and CW",
+	UsePhone:     "SSB phone, plus AM and wide digital voice where permitted, and CW",
+	UseAllModes:  "any mode, including phone, CW and digital modes",
+	UseBeacons:   "propagation beacons only; do not transmit here",
+	UseSatellite: "amateur satellite links only",
+	UseFM:        "FM simplex, repeaters and packet",
+}
+
+// Segment is part of a band set aside for one use
+type Segment struct {
+	LowKHz  float64 `json:"lowKHz"`
+	HighKHz float64 `json:"highKHz"`
+	Use     string  `json:"use"`
+}
+
+// Modes describes the modes expected in the segment
+func (s Segment) Modes() string {
+	return useModes[s.Use]
+}
+
+// Band is an amateur band in one region
+type Band struct {
+	Name     string    `json:"name"`
+	LowKHz   float64   `json:"lowKHz"`
+	HighKHz  float64   `json:"highKHz"`
+	Segments []Segment `json:"segments"`
+}
+
+// Segment returns the segment containing a frequency in kHz
+func (b *Band) Segment(khz float64) (*Segment, bool) {
+	for i := range b.Segments {
+		s := &b.Segments[i]
+		// The upper edge belongs to the next segment, except at the band edge
+		if khz >= s.LowKHz && (khz < s.HighKHz || khz == s.HighKHz && khz == b.HighKHz) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Plan is the band plan of one IARU region, with the bands lowest first
+type Plan struct {
+	Region int    `json:"region"`
+	Bands  []Band `json:"bands"`
+}
+
+// ForRegion returns the band plan of IARU region 1, 2 or 3
+func ForRegion(region int) (*Plan, error) {
+	plan, ok := plans[region]
+	if !ok {
+		return nil, fmt.Errorf("unknown IARU region %d (expected 1, 2 or 3)", region)
+	}
+	return plan, nil
+}
+
+// Band returns the band containing a frequency in kHz
+func (p *Plan) Band(khz float64) (*Band, bool) {
+	for i := range p.Bands {
+		b := &p.Bands[i]
+		if khz >= b.LowKHz && khz <= b.HighKHz {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// BandByName returns a band by its name, accepting meter bands without the
+// unit (20 for 20m) and any letter case
+func (p *Plan) BandByName(name string) (*Band, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i := range p.Bands {
+		b := &p.Bands[i]
+		if name == b.Name || name+"m" == b.Name {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// Classification places a frequency in a band plan
+type Classification struct {
+	Region       int
+	FrequencyKHz float64
+	// Band and Segment are nil outside the amateur bands
+	Band    *Band
+	Segment *Segment
+	// Dials are the digital mode dial frequencies whose passband contains
+	// the frequency
+	Dials []DialFrequency
+}
+
+// Classify places a frequency in kHz in the band plan of a region, or of
+// DefaultRegion when region is zero
+func Classify(region int, khz float64) (Classification, error) {
+	if region == 0 {
+		region = DefaultRegion
+	}
+	plan, err := ForRegion(region)
+	if err != nil {
+		return Classification{}, err
+	}
+
+	c := Classification{Region: region, FrequencyKHz: khz}
+	if band, ok := plan.Band(khz); ok {
+		c.Band = band
+		if segment, ok := band.Segment(khz); ok {
+			c.Segment = segment
+		}
+		c.Dials = DialsAt(khz)
+	}
+	return c, nil
+}
+
+// BandName returns the name of the band containing a frequency in kHz in
+// the region, or DefaultRegion when region is zero, or an empty string
+// outside the amateur bands
+func BandName(region int, khz float64) string {
+	c, err := Classify(region, khz)
+	if err != nil || c.Band == nil {
+		return ""
+	}
+	return c.Band.Name
+}
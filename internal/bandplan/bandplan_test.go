@@ -0,0 +1,215 @@
+package bandplan
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		region  int
+		khz     float64
+		band    string
+		segment string
+	}{
+		{0, 14074, "20m", UseData},
+		{2, 14074, "20m", UseData},
+		// The upper edge of a segment belongs to the next one
+		{2, 14070, "20m", UseData},
+		{2, 14069.9, "20m", UseCW},
+		{2, 14000, "20m", UseCW},
+		// The upper band edge belongs to the last segment
+		{2, 14350, "20m", UsePhone},
+		{2, 14350.1, "", ""},
+		{2, 13999.9, "", ""},
+		{2, 14100, "20m", UseBeacons},
+		// Regions differ in band edges and segments
+		{1, 7150, "40m", UsePhone},
+		{1, 7250, "", ""},
+		{2, 7250, "40m", UsePhone},
+		{1, 3550, "80m", UseCW},
+		{3, 3550, "80m", UseData},
+		{1, 1805, "", ""},
+		{2, 1805, "160m", UseCW},
+		{2, 5332, "60m", UseAllModes},
+		{1, 5332, "", ""},
+		{1, 5352, "60m", UseCW},
+		{2, 223500, "1.25m", UseFM},
+		{1, 223500, "", ""},
+		{2, 146520, "2m", UseFM},
+		{1, 145500, "2m", UseFM},
+		{2, 1296100, "23cm", UseCW},
+		// Digital mode dials are outside the phone segments
+		{1, 7074, "40m", UseAllModes},
+		{2, 7078, "40m", UseAllModes},
+		{3, 7150, "40m", UsePhone},
+		{1, 144174, "2m", UseData},
+		{2, 144174, "2m", UseData},
+		{2, 144200, "2m", UsePhone},
+		{3, 50313, "6m", UseData},
+	}
+
+	for _, tt := range tests {
+		c, err := Classify(tt.region, tt.khz)
+		if err != nil {
+			t.Errorf("Classify(%d, %v): %v", tt.region, tt.khz, err)
+			continue
+		}
+		var band, segment string
+		if c.Band != nil {
+			band = c.Band.Name
+		}
+		if c.Segment != nil {
+			segment = c.Segment.Use
+		}
+		if band != tt.band || segment != tt.segment {
+			t.Errorf("Classify(%d, %v) = %q %q, want %q %q", tt.region, tt.khz, band, segment, tt.band, tt.segment)
+		}
+		want := tt.region
+		if want == 0 {
+			want = DefaultRegion
+		}
+		if c.Region != want {
+			t.Errorf("Classify(%d, %v).Region = %d, want %d", tt.region, tt.khz, c.Region, want)
+		}
+	}
+}
+
+func TestClassifyUnknownRegion(t *testing.T) {
+	for _, region := range []int{-1, 4} {
+		if _, err := Classify(region, 14074); err == nil {
+			t.Errorf("Classify(%d) succeeded, want an error", region)
+		}
+	}
+}
+
+func TestClassifyDials(t *testing.T) {
+	tests := []struct {
+		khz   float64
+		modes []string
+	}{
+		{14074, []string{ModeFT8}},
+		{14075.5, []string{ModeFT8}},
+		{14077, []string{ModeFT8}},
+		{14078, []string{ModeJS8}},
+		{18105, []string{ModeFT4, ModeJS8, ModeWSPR}},
+		{14200, nil},
+	}
+
+	for _, tt := range tests {
+		c, err := Classify(2, tt.khz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var modes []string
+		for _, dial := range c.Dials {
+			modes = append(modes, dial.Mode)
+		}
+		if !slices.Equal(modes, tt.modes) {
+			t.Errorf("Classify(%v).Dials = %v, want %v", tt.khz, modes, tt.modes)
+		}
+	}
+}
+
+func TestPlansCoverBandsWithoutGaps(t *testing.T) {
+	for region := 1; region <= 3; region++ {
+		plan, err := ForRegion(region)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, b := range plan.Bands {
+			if i > 0 && b.LowKHz <= plan.Bands[i-1].HighKHz {
+				t.Errorf("region %d: %s overlaps %s", region, b.Name, plan.Bands[i-1].Name)
+			}
+			for j, s := range b.Segments {
+				if s.LowKHz >= s.HighKHz {
+					t.Errorf("region %d %s: empty segment %v", region, b.Name, s)
+				}
+				if j > 0 && s.LowKHz != b.Segments[j-1].HighKHz {
+					t.Errorf("region %d %s: gap or overlap at %v", region, b.Name, s.LowKHz)
+				}
+				if s.Modes() == "" {
+					t.Errorf("region %d %s: segment use %q has no modes", region, b.Name, s.Use)
+				}
+			}
+		}
+	}
+}
+
+func TestDialsFallInTheirBand(t *testing.T) {
+	// The default region's bands hold those of every region
+	plan, _ := ForRegion(DefaultRegion)
+	for _, dial := range dialFrequencies {
+		band, ok := plan.Band(dial.KHz)
+		if !ok || band.Name != dial.Band {
+			t.Errorf("%s dial %v is not in %s", dial.Mode, dial.KHz, dial.Band)
+		}
+	}
+}
+
+func TestDialsFallInDigitalSegments(t *testing.T) {
+	for region := 1; region <= 3; region++ {
+		plan, _ := ForRegion(region)
+		for _, dial := range dialFrequencies {
+			khz := dial.KHz
+			if dial.Mode == ModeWSPR {
+				// WSPR dials sit below the data segments, with the signals
+				// 1.4 to 1.6 kHz above the dial
+				khz += 1.5
+			}
+			band, ok := plan.BandByName(dial.Band)
+			if !ok {
+				continue
+			}
+			segment, ok := band.Segment(khz)
+			if !ok || segment.Use != UseData && segment.Use != UseAllModes {
+				t.Errorf("region %d: %s %s dial %v is in a %v segment", region, dial.Band, dial.Mode, dial.KHz, segment)
+			}
+		}
+	}
+}
+
+func TestBandByName(t *testing.T) {
+	plan, _ := ForRegion(2)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"20m", "20m"},
+		{"20", "20m"},
+		{" 70CM ", "70cm"},
+		{"1.25m", "1.25m"},
+		{"11m", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		var got string
+		if band, ok := plan.BandByName(tt.name); ok {
+			got = band.Name
+		}
+		if got != tt.want {
+			t.Errorf("BandByName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDialsInBand(t *testing.T) {
+	var got []float64
+	for _, dial := range DialsInBand("40m") {
+		got = append(got, dial.KHz)
+	}
+	want := []float64{7038.6, 7047.5, 7074, 7078}
+	if !slices.Equal(got, want) {
+		t.Errorf("DialsInBand(40m) = %v, want %v", got, want)
+	}
+}
+
+func TestBandName(t *testing.T) {
+	if got := BandName(0, 50313); got != "6m" {
+		t.Errorf("BandName(0, 50313) = %q, want 6m", got)
+	}
+	if got := BandName(1, 7250); got != "" {
+		t.Errorf("BandName(1, 7250) = %q, want none", got)
+	}
+}
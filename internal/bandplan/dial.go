@@ -0,0 +1,72 @@
+package bandplan
+
+import "sort"
+
+// Digital modes with conventional dial frequencies
+const (
+	ModeFT8  = "FT8"
+	ModeFT4  = "FT4"
+	ModeJS8  = "JS8"
+	ModeWSPR = "WSPR"
+)
+
+// passbandKHz is the width of the audio passband above a USB dial frequency
+// in which the digital mode signals are found
+const passbandKHz = 3
+
+// DialFrequency is the USB dial frequency a digital mode is conventionally
+// operated on in a band
+type DialFrequency struct {
+	Mode string  `json:"mode"`
+	Band string  `json:"band"`
+	KHz  float64 `json:"kHz"`
+}
+
+// dialFrequencies lists the dial frequencies used worldwide, as published
+// with WSJT-X and JS8Call
+var dialFrequencies = []DialFrequency{
+	{ModeFT8, "160m", 1840}, {ModeFT8, "80m", 3573}, {ModeFT8, "60m", 5357},
+	{ModeFT8, "40m", 7074}, {ModeFT8, "30m", 10136}, {ModeFT8, "20m", 14074},
+	{ModeFT8, "17m", 18100}, {ModeFT8, "15m", 21074}, {ModeFT8, "12m", 24915},
+	{ModeFT8, "10m", 28074}, {ModeFT8, "6m", 50313}, {ModeFT8, "2m", 144174},
+
+	{ModeFT4, "80m", 3575}, {ModeFT4, "40m", 7047.5}, {ModeFT4, "30m", 10140},
+	{ModeFT4, "20m", 14080}, {ModeFT4, "17m", 18104}, {ModeFT4, "15m", 21140},
+	{ModeFT4, "12m", 24919}, {ModeFT4, "10m", 28180}, {ModeFT4, "6m", 50318},
+	{ModeFT4, "2m", 144170},
+
+	{ModeJS8, "160m", 1842}, {ModeJS8, "80m", 3578}, {ModeJS8, "40m", 7078},
+	{ModeJS8, "30m", 10130}, {ModeJS8, "20m", 14078}, {ModeJS8, "17m", 18104},
+	{ModeJS8, "15m", 21078}, {ModeJS8, "12m", 24922}, {ModeJS8, "10m", 28078},
+	{ModeJS8, "6m", 50318}, {ModeJS8, "2m", 144178},
+
+	{ModeWSPR, "160m", 1836.6}, {ModeWSPR, "80m", 3568.6}, {ModeWSPR, "60m", 5364.7},
+	{ModeWSPR, "40m", 7038.6}, {ModeWSPR, "30m", 10138.7}, {ModeWSPR, "20m", 14095.6},
+	{ModeWSPR, "17m", 18104.6}, {ModeWSPR, "15m", 21094.6}, {ModeWSPR, "12m", 24924.6},
+	{ModeWSPR, "10m", 28124.6}, {ModeWSPR, "6m", 50293}, {ModeWSPR, "2m", 144489},
+}
+
+// DialsAt returns the dial frequencies whose passband contains a frequency
+// in kHz, which may be either the dial frequency itself or the frequency of
+// a signal in the passband
+func DialsAt(khz float64) []DialFrequency {
+	var dials []DialFrequency
+	for _, dial := range dialFrequencies {
+		if khz >= dial.KHz && khz <= dial.KHz+passbandKHz {
+			dials = append(dials, dial)
+		}
+	}
+	return dials
+}
+
+// DialsInBand returns the dial frequencies in a band, lowest first
+func DialsInBand(band string) []DialFrequency {
+	var dials []DialFrequency
+	for _, dial := range dialFrequencies {
+		if dial.Band == band {
+			dials = append(dials, dial)
+		}
+	}
+	sort.SliceStable(dials, func(i, j int) bool { return dials[i].KHz < dials[j].KHz })
+	return dials
+}
@@ -0,0 +1,217 @@
+package bandplan
+
+// band builds a band from its segments, which must cover it without gaps
+func band(name string, segments ...Segment) Band {
+	return Band{
+		Name:     name,
+		LowKHz:   segments[0].LowKHz,
+		HighKHz:  segments[len(segments)-1].HighKHz,
+		Segments: segments,
+	}
+}
+
+// Bands that are planned alike in every region
+var (
+	band30m = band("30m",
+		Segment{10100, 10130, UseCW},
+		Segment{10130, 10150, UseData},
+	)
+	band20m = band("20m",
+		Segment{14000, 14070, UseCW},
+		Segment{14070, 14099, UseData},
+		Segment{14099, 14101, UseBeacons},
+		Segment{14101, 14350, UsePhone},
+	)
+	band17m = band("17m",
+		Segment{18068, 18095, UseCW},
+		Segment{18095, 18109, UseData},
+		Segment{18109, 18111, UseBeacons},
+		Segment{18111, 18168, UsePhone},
+	)
+	band15m = band("15m",
+		Segment{21000, 21070, UseCW},
+		Segment{21070, 21149, UseData},
+		Segment{21149, 21151, UseBeacons},
+		Segment{21151, 21450, UsePhone},
+	)
+	band12m = band("12m",
+		Segment{24890, 24915, UseCW},
+		Segment{24915, 24929, UseData},
+		Segment{24929, 24931, UseBeacons},
+		Segment{24931, 24990, UsePhone},
+	)
+	band10m = band("10m",
+		Segment{28000, 28070, UseCW},
+		Segment{28070, 28190, UseData},
+		Segment{28190, 28225, UseBeacons},
+		Segment{28225, 29200, UsePhone},
+		Segment{29200, 29300, UseData},
+		Segment{29300, 29510, UseSatellite},
+		Segment{29510, 29700, UseFM},
+	)
+	band60mWRC = band("60m",
+		Segment{5351.5, 5354, UseCW},
+		Segment{5354, 5366, UseAllModes},
+		Segment{5366, 5366.5, UseData},
+	)
+	band23cm = band("23cm",
+		Segment{1240000, 1296000, UseAllModes},
+		Segment{1296000, 1296150, UseCW},
+		Segment{1296150, 1296800, UsePhone},
+		Segment{1296800, 1297000, UseBeacons},
+		Segment{1297000, 1300000, UseFM},
+	)
+)
+
+// plans are the band plans by IARU region
+var plans = map[int]*Plan{
+	1: {Region: 1, Bands: []Band{
+		band("160m",
+			Segment{1810, 1838, UseCW},
+			Segment{1838, 1843, UseData},
+			Segment{1843, 2000, UsePhone},
+		),
+		band("80m",
+			Segment{3500, 3570, UseCW},
+			Segment{3570, 3600, UseData},
+			Segment{3600, 3800, UsePhone},
+		),
+		band60mWRC,
+		band("40m",
+			Segment{7000, 7040, UseCW},
+			Segment{7040, 7060, UseData},
+			Segment{7060, 7100, UseAllModes},
+			Segment{7100, 7200, UsePhone},
+		),
+		band30m, band20m, band17m, band15m, band12m, band10m,
+		band("6m",
+			Segment{50000, 50100, UseCW},
+			Segment{50100, 50280, UsePhone},
+			Segment{50280, 50500, UseData},
+			Segment{50500, 51000, UseAllModes},
+			Segment{51000, 52000, UseFM},
+		),
+		band("2m",
+			Segment{144000, 144150, UseCW},
+			Segment{144150, 144160, UsePhone},
+			Segment{144160, 144180, UseData},
+			Segment{144180, 144400, UsePhone},
+			Segment{144400, 144490, UseBeacons},
+			Segment{144490, 144794, UseAllModes},
+			Segment{144794, 144990, UseData},
+			Segment{144990, 145800, UseFM},
+			Segment{145800, 146000, UseSatellite},
+		),
+		band("70cm",
+			Segment{430000, 432000, UseAllModes},
+			Segment{432000, 432100, UseCW},
+			Segment{432100, 432400, UsePhone},
+			Segment{432400, 432490, UseBeacons},
+			Segment{432490, 435000, UseAllModes},
+			Segment{435000, 438000, UseSatellite},
+			Segment{438000, 440000, UseFM},
+		),
+		band23cm,
+	}},
+	2: {Region: 2, Bands: []Band{
+		band("160m",
+			Segment{1800, 1838, UseCW},
+			Segment{1838, 1850, UseData},
+			Segment{1850, 2000, UsePhone},
+		),
+		band("80m",
+			Segment{3500, 3570, UseCW},
+			Segment{3570, 3600, UseData},
+			Segment{3600, 4000, UsePhone},
+		),
+		// The US channels extend the band below and above the WRC-15
+		// allocation; every mode shares them
+		band("60m",
+			Segment{5330.5, 5406.5, UseAllModes},
+		),
+		band("40m",
+			Segment{7000, 7040, UseCW},
+			Segment{7040, 7060, UseData},
+			Segment{7060, 7100, UseAllModes},
+			Segment{7100, 7300, UsePhone},
+		),
+		band30m, band20m, band17m, band15m, band12m, band10m,
+		band("6m",
+			Segment{50000, 50100, UseCW},
+			Segment{50100, 50280, UsePhone},
+			Segment{50280, 50600, UseData},
+			Segment{50600, 51000, UseAllModes},
+			Segment{51000, 54000, UseFM},
+		),
+		band("2m",
+			Segment{144000, 144100, UseCW},
+			Segment{144100, 144160, UsePhone},
+			Segment{144160, 144180, UseData},
+			Segment{144180, 144275, UsePhone},
+			Segment{144275, 144300, UseBeacons},
+			Segment{144300, 145800, UseAllModes},
+			Segment{145800, 146000, UseSatellite},
+			Segment{146000, 148000, UseFM},
+		),
+		band("1.25m",
+			Segment{222000, 222150, UseAllModes},
+			Segment{222150, 225000, UseFM},
+		),
+		band("70cm",
+			Segment{420000, 432000, UseAllModes},
+			Segment{432000, 432070, UseCW},
+			Segment{432070, 432300, UsePhone},
+			Segment{432300, 432400, UseBeacons},
+			Segment{432400, 435000, UseAllModes},
+			Segment{435000, 438000, UseSatellite},
+			Segment{438000, 450000, UseFM},
+		),
+		band23cm,
+	}},
+	3: {Region: 3, Bands: []Band{
+		band("160m",
+			Segment{1800, 1838, UseCW},
+			Segment{1838, 1843, UseData},
+			Segment{1843, 2000, UsePhone},
+		),
+		band("80m",
+			Segment{3500, 3535, UseCW},
+			Segment{3535, 3600, UseData},
+			Segment{3600, 3900, UsePhone},
+		),
+		band60mWRC,
+		band("40m",
+			Segment{7000, 7040, UseCW},
+			Segment{7040, 7060, UseData},
+			Segment{7060, 7100, UseAllModes},
+			Segment{7100, 7300, UsePhone},
+		),
+		band30m, band20m, band17m, band15m, band12m, band10m,
+		band("6m",
+			Segment{50000, 50100, UseCW},
+			Segment{50100, 50280, UsePhone},
+			Segment{50280, 50500, UseData},
+			Segment{50500, 51000, UseAllModes},
+			Segment{51000, 54000, UseFM},
+		),
+		band("2m",
+			Segment{144000, 144100, UseCW},
+			Segment{144100, 144160, UsePhone},
+			Segment{144160, 144180, UseData},
+			Segment{144180, 144300, UsePhone},
+			Segment{144300, 144500, UseAllModes},
+			Segment{144500, 145800, UseFM},
+			Segment{145800, 146000, UseSatellite},
+			Segment{146000, 148000, UseFM},
+		),
+		band("70cm",
+			Segment{430000, 432000, UseAllModes},
+			Segment{432000, 432100, UseCW},
+			Segment{432100, 432400, UsePhone},
+			Segment{432400, 435000, UseAllModes},
+			Segment{435000, 438000, UseSatellite},
+			Segment{438000, 450000, UseFM},
+		),
+		band23cm,
+	}},
+}
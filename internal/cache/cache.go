@@ -0,0 +1,373 @@
+// Package cache provides a TTL cache for upstream responses. Entries are
+// grouped into named sources (e.g. callsign, park, spots), each with its own
+// time to live, and can be persisted to disk so they survive restarts.
+// Concurrent requests for the same missing entry share a single load, which
+// is not canceled when any one of them gives up.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// flushInterval is how often changed entries are written to disk
+const flushInterval = time.Minute
+
+// entry is a cached value, stored as JSON so that any type can be cached and
+// persisted
+type entry struct {
+	Value   json.RawMessage `json:"value"`
+	Stored  time.Time       `json:"stored"`
+	Expires time.Time       `json:"expires"`
+}
+
+// counters holds the hit and miss counts of one source
+type counters struct {
+	hits   uint64
+	misses uint64
+}
+
+// Stats describes one cache source
+type Stats struct {
+	Source  string
+	TTL     time.Duration
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+// HitRatio returns the fraction of lookups answered from the cache
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Cache is a TTL cache of JSON-encodable values
+type Cache struct {
+	ttls        map[string]time.Duration
+	path        string
+	loadTimeout time.Duration
+	group       singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]map[string]entry
+	stats   map[string]*counters
+	dirty   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a cache with a time to live for each source. Values from
+// sources without a positive TTL are never cached. When path is not empty
+// the cache is loaded from that file and written back to it periodically
+// and on Close. loadTimeout bounds a load shared by concurrent callers,
+// which runs apart from their contexts; zero leaves it unbounded.
+func New(ttls map[string]time.Duration, path string, loadTimeout time.Duration) (*Cache, error) {
+	c := &Cache{
+		ttls:        ttls,
+		path:        path,
+		loadTimeout: loadTimeout,
+		entries:     make(map[string]map[string]entry),
+		stats:       make(map[string]*counters),
+	}
+	for source := range ttls {
+		c.stats[source] = &counters{}
+	}
+
+	if path != "" {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+		c.stop = make(chan struct{})
+		c.done = make(chan struct{})
+		go c.flushLoop()
+	}
+
+	return c, nil
+}
+
+// Get returns the cached value for the key, calling load to fetch and cache
+// it when it is missing or expired. Errors are not cached. A nil cache
+// always calls load.
+func Get[T any](ctx context.Context, c *Cache, source, key string, load func(context.Context) (T, error)) (T, error) {
+	return GetFresh(ctx, c, source, key, 0, load)
+}
+
+// GetFresh is like Get, but also calls load when the cached value is older
+// than maxAge, so that a caller can require fresher values than the
+// source's TTL gives. A maxAge of zero accepts any unexpired value.
+//
+// The load keeps the values of the first caller's context but not its
+// cancellation, so a caller that gives up does not fail the others waiting
+// for the same key; each caller returns as soon as its own context is done.
+func GetFresh[T any](ctx context.Context, c *Cache, source, key string, maxAge time.Duration, load func(context.Context) (T, error)) (T, error) {
+	var value T
+	if c == nil || c.ttls[source] <= 0 {
+		return load(ctx)
+	}
+
+	if raw, ok := c.lookup(source, key, maxAge); ok {
+		if err := json.Unmarshal(raw, &value); err == nil {
+			return value, nil
+		}
+	}
+
+	// Concurrent misses for the same key wait for the first caller's load
+	results := c.group.DoChan(source+"\x00"+key, func() (any, error) {
+		loadCtx, cancel := c.loadContext(ctx)
+		defer cancel()
+		loaded, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding cache entry: %v", err)
+		}
+		c.store(source, key, raw)
+		return json.RawMessage(raw), nil
+	})
+
+	var raw any
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return value, result.Err
+		}
+		raw = result.Val
+	}
+
+	// Each caller decodes its own copy so that callers cannot share mutations
+	if err := json.Unmarshal(raw.(json.RawMessage), &value); err != nil {
+		return value, fmt.Errorf("error decoding cache entry: %v", err)
+	}
+	return value, nil
+}
+
+// loadContext returns the context of a shared load: the caller's values
+// without its cancellation, bounded by the load timeout
+func (c *Cache) loadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if c.loadTimeout > 0 {
+		return context.WithTimeout(ctx, c.loadTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// lookup returns an unexpired entry no older than maxAge, when maxAge is
+// not zero, and counts the hit or miss
+func (c *Cache) lookup(source, key string, maxAge time.Duration) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	e, ok := c.entries[source][key]
+	if ok && now.After(e.Expires) {
+		delete(c.entries[source], key)
+		c.dirty = true
+		ok = false
+	}
+	if ok && maxAge > 0 && now.Sub(e.Stored) > maxAge {
+		ok = false
+	}
+
+	if ok {
+		c.stats[source].hits++
+		return e.Value, true
+	}
+	c.stats[source].misses++
+	return nil, false
+}
+
+// store adds an entry that expires after the source's TTL
+func (c *Cache) store(source, key string, value json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries[source] == nil {
+		c.entries[source] = make(map[string]entry)
+	}
+	now := time.Now()
+	c.entries[source][key] = entry{Value: value, Stored: now, Expires: now.Add(c.ttls[source])}
+	c.dirty = true
+}
+
+// Stats returns the statistics of every source, sorted by name
+func (c *Cache) Stats() []Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var stats []Stats
+	for source, ttl := range c.ttls {
+		s := Stats{Source: source, TTL: ttl, Hits: c.stats[source].hits, Misses: c.stats[source].misses}
+		for _, e := range c.entries[source] {
+			if now.Before(e.Expires) {
+				s.Entries++
+			}
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Source < stats[j].Source })
+	return stats
+}
+
+// Sources returns the names of the cache sources, sorted
+func (c *Cache) Sources() []string {
+	sources := make([]string, 0, len(c.ttls))
+	for source := range c.ttls {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// Clear removes every entry of a source, or of all sources when source is
+// empty, and returns the number of entries removed
+func (c *Cache) Clear(source string) (int, error) {
+	if _, ok := c.ttls[source]; source != "" && !ok {
+		return 0, fmt.Errorf("unknown cache source %q", source)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for name, entries := range c.entries {
+		if source == "" || name == source {
+			removed += len(entries)
+			delete(c.entries, name)
+		}
+	}
+	c.dirty = true
+
+	return removed, nil
+}
+
+// Flush writes the cache to disk when it has changed since the last write.
+// It does nothing when the cache is not persisted.
+func (c *Cache) Flush() error {
+	if c == nil || c.path == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	if !c.dirty {
+		c.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	live := make(map[string]map[string]entry, len(c.entries))
+	for source, entries := range c.entries {
+		live[source] = make(map[string]entry, len(entries))
+		for key, e := range entries {
+			if now.Before(e.Expires) {
+				live[source][key] = e
+			}
+		}
+	}
+	c.dirty = false
+	c.mu.Unlock()
+
+	data, err := json.Marshal(live)
+	if err != nil {
+		return fmt.Errorf("error encoding cache: %v", err)
+	}
+
+	// Write to a temporary file first so that a crash cannot leave a
+	// truncated cache behind
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("error writing cache: %v", err)
+	}
+
+	return nil
+}
+
+// Close stops the periodic writes and writes the cache to disk one last time
+func (c *Cache) Close() error {
+	if c == nil || c.stop == nil {
+		return nil
+	}
+	close(c.stop)
+	<-c.done
+	return c.Flush()
+}
+
+// load reads the persisted cache, skipping expired entries and sources that
+// are no longer cached. A missing file is not an error.
+func (c *Cache) load() error {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading cache: %v", err)
+	}
+
+	var persisted map[string]map[string]entry
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return fmt.Errorf("error parsing cache %s: %v", c.path, err)
+	}
+
+	now := time.Now()
+	for source, entries := range persisted {
+		if c.ttls[source] <= 0 {
+			continue
+		}
+		for key, e := range entries {
+			if now.Before(e.Expires) {
+				if c.entries[source] == nil {
+					c.entries[source] = make(map[string]entry)
+				}
+				c.entries[source][key] = e
+			}
+		}
+	}
+
+	return nil
+}
+
+// flushLoop writes the cache to disk periodically until Close is called
+func (c *Cache) flushLoop() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if err := c.Flush(); err != nil {
+				slog.Warn("error writing cache", "path", c.path, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCache(t *testing.T, path string) *Cache {
+	t.Helper()
+	c, err := New(map[string]time.Duration{"spots": time.Hour}, path, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestGetSharesOneLoad(t *testing.T) {
+	c := newCache(t, "")
+	release := make(chan struct{})
+	var loads atomic.Int32
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	// A caller that misses the shared load finds its stored value instead
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := Get(context.Background(), c, "spots", "key", load)
+			if err == nil && value != "value" {
+				err = errors.New("got " + value)
+			}
+			errs <- err
+		}()
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Get: %v", err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loads = %d, want 1", n)
+	}
+}
+
+func TestGetCanceledCallerDoesNotFailOthers(t *testing.T) {
+	c := newCache(t, "")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(ctx context.Context) (string, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-release:
+			return "value", nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := Get(ctx, c, "spots", "key", load)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	var value string
+	go func() {
+		var err error
+		value, err = Get(context.Background(), c, "spots", "key", load)
+		second <- err
+	}()
+	// Wait for the second caller to miss the cache and join the load
+	for c.Stats()[0].Misses < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("first Get error = %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-second; err != nil || value != "value" {
+		t.Errorf("second Get = %q, %v, want value", value, err)
+	}
+}
+
+func TestGetDoesNotCacheErrors(t *testing.T) {
+	c := newCache(t, "")
+	loads := 0
+	load := func(context.Context) (int, error) {
+		loads++
+		if loads == 1 {
+			return 0, errors.New("unavailable")
+		}
+		return loads, nil
+	}
+
+	if _, err := Get(context.Background(), c, "spots", "key", load); err == nil {
+		t.Error("first Get succeeded, want an error")
+	}
+	if value, err := Get(context.Background(), c, "spots", "key", load); err != nil || value != 2 {
+		t.Errorf("second Get = %d, %v, want 2", value, err)
+	}
+	if value, err := Get(context.Background(), c, "spots", "key", load); err != nil || value != 2 {
+		t.Errorf("third Get = %d, %v, want the cached 2", value, err)
+	}
+}
+
+func TestGetFreshMaxAge(t *testing.T) {
+	c := newCache(t, "")
+	loads := 0
+	load := func(context.Context) (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	if value, _ := Get(context.Background(), c, "spots", "key", load); value != 1 {
+		t.Errorf("Get = %d, want 1", value)
+	}
+	if value, _ := GetFresh(context.Background(), c, "spots", "key", time.Hour, load); value != 1 {
+		t.Errorf("GetFresh(1h) = %d, want the cached 1", value)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if value, _ := GetFresh(context.Background(), c, "spots", "key", time.Millisecond, load); value != 2 {
+		t.Errorf("GetFresh(1ms) = %d, want a reload", value)
+	}
+	if value, _ := Get(context.Background(), c, "spots", "key", load); value != 2 {
+		t.Errorf("Get = %d, want the reloaded 2", value)
+	}
+}
+
+func TestCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c := newCache(t, path)
+	load := func(context.Context) ([]string, error) { return []string{"W1AW", "K1ABC"}, nil }
+	if _, err := Get(context.Background(), c, "spots", "key", load); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reopened cache answers from the file without loading
+	reopened := newCache(t, path)
+	value, err := Get(context.Background(), reopened, "spots", "key", func(context.Context) ([]string, error) {
+		return nil, errors.New("loaded")
+	})
+	if err != nil || len(value) != 2 || value[1] != "K1ABC" {
+		t.Errorf("Get after reopening = %q, %v, want [W1AW K1ABC]", value, err)
+	}
+
+	// Sources that are no longer cached are dropped
+	uncached, err := New(map[string]time.Duration{"park": time.Hour}, path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uncached.Close()
+	if stats := uncached.Stats(); stats[0].Entries != 0 {
+		t.Errorf("park entries = %d, want 0", stats[0].Entries)
+	}
+	if len(uncached.entries) != 0 {
+		t.Errorf("entries = %v, want none", uncached.entries)
+	}
+}
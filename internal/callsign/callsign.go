@@ -0,0 +1,179 @@
+// Package callsign parses amateur radio callsigns, including portable,
+// mobile and reciprocal-licence forms such as VE3/W1AW/P and KH6/K1ABC
+package callsign
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// basePattern matches a home callsign: a prefix of one to three characters,
+// a separating digit, and a suffix ending in a letter (e.g. W1AW, 3DA0XY, 2E0ABC)
+var basePattern = regexp.MustCompile(`^[A-Z0-9]{1,3}[0-9][A-Z0-9]{0,3}[A-Z]$`)
+
+// prefixPattern matches a prefix designator used in front of or after a call
+var prefixPattern = regexp.MustCompile(`^[A-Z0-9]{1,4}$`)
+
+// modifiers are suffixes that describe how the station operates rather than where
+var modifiers = map[string]string{
+	"P":   "portable",
+	"M":   "mobile",
+	"MM":  "maritime mobile",
+	"AM":  "aeronautical mobile",
+	"QRP": "low power",
+	"A":   "alternate location",
+	"B":   "beacon",
+	"R":   "repeater",
+	"LH":  "lighthouse",
+}
+
+// ErrInvalid is returned for strings that are not valid callsigns
+var ErrInvalid = errors.New("invalid callsign")
+
+// Callsign is a parsed amateur radio callsign
+type Callsign struct {
+	// Full is the normalized callsign as given, e.g. "VE3/W1AW/P"
+	Full string `json:"full"`
+	// Prefix is the prefix designator in front of the base call, e.g. "VE3"
+	Prefix string `json:"prefix,omitempty"`
+	// Base is the station's home callsign, e.g. "W1AW"
+	Base string `json:"base"`
+	// Suffix is the designator after the base call, either a modifier such
+	// as "P" or "M", or a location such as "KH6" or a call area digit
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// Parse parses and normalizes a callsign. Input is case-insensitive and may
+// carry a prefix and/or suffix separated by slashes.
+func Parse(s string) (*Callsign, error) {
+	full := strings.ToUpper(strings.TrimSpace(s))
+	if full == "" {
+		return nil, fmt.Errorf("%w: empty callsign", ErrInvalid)
+	}
+
+	c := &Callsign{Full: full}
+	parts := strings.Split(full, "/")
+	switch len(parts) {
+	case 1:
+		c.Base = parts[0]
+	case 2:
+		first, second := parts[0], parts[1]
+		switch {
+		case isModifier(second) || isCallArea(second):
+			c.Base, c.Suffix = first, second
+		case len(first) < len(second) || !basePattern.MatchString(first):
+			// The shorter part is the prefix designator (e.g. KH6/K1ABC)
+			c.Prefix, c.Base = first, second
+		case len(first) == len(second) && basePattern.MatchString(second) && callSuffixLen(second) > callSuffixLen(first):
+			// Of two parts of one length, the call has the longer suffix
+			// after its digit (e.g. VP2V/W1AW)
+			c.Prefix, c.Base = first, second
+		default:
+			// A location designator after the call (e.g. K1ABC/KH6)
+			c.Base, c.Suffix = first, second
+		}
+	case 3:
+		c.Prefix, c.Base, c.Suffix = parts[0], parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("%w: %s has too many parts", ErrInvalid, full)
+	}
+
+	if !basePattern.MatchString(c.Base) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalid, full)
+	}
+	if c.Prefix != "" && !prefixPattern.MatchString(c.Prefix) {
+		return nil, fmt.Errorf("%w: bad prefix %s in %s", ErrInvalid, c.Prefix, full)
+	}
+	if c.Suffix != "" && !isModifier(c.Suffix) && !prefixPattern.MatchString(c.Suffix) {
+		return nil, fmt.Errorf("%w: bad suffix %s in %s", ErrInvalid, c.Suffix, full)
+	}
+
+	return c, nil
+}
+
+// String returns the normalized full callsign
+func (c *Callsign) String() string {
+	return c.Full
+}
+
+// Modifier returns a description of the operating modifier (e.g. "portable")
+// or an empty string when the callsign has none
+func (c *Callsign) Modifier() string {
+	return modifiers[c.Suffix]
+}
+
+// IsMaritimeMobile reports whether the station is operating from a ship at sea
+func (c *Callsign) IsMaritimeMobile() bool {
+	return c.Suffix == "MM"
+}
+
+// IsAeronauticalMobile reports whether the station is operating from an aircraft
+func (c *Callsign) IsAeronauticalMobile() bool {
+	return c.Suffix == "AM"
+}
+
+// IsAway reports whether the callsign indicates operation away from the
+// home call's location through a prefix or a location suffix
+func (c *Callsign) IsAway() bool {
+	return c.Prefix != "" || c.locationSuffix() != ""
+}
+
+// HomePrefix returns the prefix of the base call up to and including its
+// separating digit (e.g. "W1" for W1AW, "3DA0" for 3DA0XY)
+func (c *Callsign) HomePrefix() string {
+	i := strings.LastIndexFunc(c.Base, unicode.IsDigit)
+	return c.Base[:i+1]
+}
+
+// OperatingPrefix returns the prefix identifying where the station is
+// operating for DXCC purposes. A prefix designator takes precedence, then a
+// location suffix, then the home prefix. A call area digit suffix replaces
+// the digit of the home prefix (W1AW/4 operates as W4). Maritime and
+// aeronautical mobile stations have no DXCC entity and return "".
+func (c *Callsign) OperatingPrefix() string {
+	if c.IsMaritimeMobile() || c.IsAeronauticalMobile() {
+		return ""
+	}
+	if c.Prefix != "" {
+		return c.Prefix
+	}
+
+	location := c.locationSuffix()
+	home := c.HomePrefix()
+	if isCallArea(location) {
+		return strings.TrimRightFunc(home, unicode.IsDigit) + location
+	}
+	if location != "" {
+		return location
+	}
+
+	return home
+}
+
+// locationSuffix returns the suffix when it designates a location rather
+// than an operating modifier
+func (c *Callsign) locationSuffix() string {
+	if c.Suffix == "" || isModifier(c.Suffix) {
+		return ""
+	}
+	return c.Suffix
+}
+
+// isModifier reports whether the designator is an operating modifier
+func isModifier(s string) bool {
+	_, ok := modifiers[s]
+	return ok
+}
+
+// callSuffixLen returns the number of characters after the last digit
+func callSuffixLen(s string) int {
+	return len(s) - strings.LastIndexFunc(s, unicode.IsDigit) - 1
+}
+
+// isCallArea reports whether the designator is a single call area digit
+func isCallArea(s string) bool {
+	return len(s) == 1 && s[0] >= '0' && s[0] <= '9'
+}
@@ -0,0 +1,96 @@
+package callsign
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input     string
+		full      string
+		prefix    string
+		base      string
+		suffix    string
+		modifier  string
+		away      bool
+		operating string
+	}{
+		{"w1aw", "W1AW", "", "W1AW", "", "", false, "W1"},
+		{" 3da0xy ", "3DA0XY", "", "3DA0XY", "", "", false, "3DA0"},
+		{"2E0ABC", "2E0ABC", "", "2E0ABC", "", "", false, "2E0"},
+		{"W1AW/P", "W1AW/P", "", "W1AW", "P", "portable", false, "W1"},
+		{"W1AW/QRP", "W1AW/QRP", "", "W1AW", "QRP", "low power", false, "W1"},
+		// A call area digit replaces the digit of the home prefix
+		{"W1AW/4", "W1AW/4", "", "W1AW", "4", "", true, "W4"},
+		// The shorter part in front is a prefix designator
+		{"KH6/K1ABC", "KH6/K1ABC", "KH6", "K1ABC", "", "", true, "KH6"},
+		{"VE3/W1AW/P", "VE3/W1AW/P", "VE3", "W1AW", "P", "portable", true, "VE3"},
+		// A longer part after a valid call is a location designator
+		{"K1ABC/KH6", "K1ABC/KH6", "", "K1ABC", "KH6", "", true, "KH6"},
+		// Of two parts of one length, the call has the longer suffix
+		{"VP2V/W1AW", "VP2V/W1AW", "VP2V", "W1AW", "", "", true, "VP2V"},
+		{"W1AW/VP2V", "W1AW/VP2V", "", "W1AW", "VP2V", "", true, "VP2V"},
+		// Maritime and aeronautical mobile stations have no DXCC prefix
+		{"K1ABC/MM", "K1ABC/MM", "", "K1ABC", "MM", "maritime mobile", false, ""},
+		{"K1ABC/AM", "K1ABC/AM", "", "K1ABC", "AM", "aeronautical mobile", false, ""},
+	}
+
+	for _, tt := range tests {
+		c, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		got := [...]string{c.Full, c.Prefix, c.Base, c.Suffix, c.Modifier(), c.OperatingPrefix()}
+		want := [...]string{tt.full, tt.prefix, tt.base, tt.suffix, tt.modifier, tt.operating}
+		if got != want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, want)
+		}
+		if c.IsAway() != tt.away {
+			t.Errorf("Parse(%q).IsAway() = %v, want %v", tt.input, c.IsAway(), tt.away)
+		}
+	}
+}
+
+func TestParseMobileFlags(t *testing.T) {
+	tests := []struct {
+		input        string
+		maritime     bool
+		aeronautical bool
+	}{
+		{"K1ABC/MM", true, false},
+		{"K1ABC/AM", false, true},
+		{"K1ABC/M", false, false},
+	}
+
+	for _, tt := range tests {
+		c, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		if c.IsMaritimeMobile() != tt.maritime || c.IsAeronauticalMobile() != tt.aeronautical {
+			t.Errorf("Parse(%q) maritime %v aeronautical %v, want %v %v",
+				tt.input, c.IsMaritimeMobile(), c.IsAeronauticalMobile(), tt.maritime, tt.aeronautical)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"W1",
+		"1234",
+		"W1AW1",
+		"W1A-W",
+		"TOOLONGPFX/W1AW",
+		"W1AW/BAD-",
+		"A/B/C/D",
+		"VE3/W1AW/P/QRP",
+	} {
+		if c, err := Parse(input); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) = %v, %v, want ErrInvalid", input, c, err)
+		}
+	}
+}
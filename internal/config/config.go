@@ -2,35 +2,322 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
 	Server struct {
 		Port int `json:"port"`
+		// MetricsPath is where the SSE and HTTP transports serve Prometheus
+		// metrics, next to the MCP endpoints. Empty disables the metrics.
+		MetricsPath string `json:"metrics_path"`
+		// ShutdownGrace is how long running tool calls may finish after
+		// SIGINT or SIGTERM before they are canceled, as a duration
+		// (default "5s")
+		ShutdownGrace string `json:"shutdown_grace"`
 	} `json:"server"`
+	Station  StationConfig  `json:"station"`
+	Callsign CallsignConfig `json:"callsign"`
+	DXCC     DXCCConfig     `json:"dxcc"`
+	POTA     POTAConfig     `json:"pota"`
+	Upstream UpstreamConfig `json:"upstream"`
+	Cache    CacheConfig    `json:"cache"`
+	Log      LogConfig      `json:"log"`
+	// Tools configures individual tools by name (e.g. pota-spots)
+	Tools map[string]ToolConfig `json:"tools"`
 }
 
-// Load reads the config file and returns the configuration
+// LogConfig configures the structured log, which includes an audit record
+// of every tool call
+type LogConfig struct {
+	// Level is debug, info (the default), warn or error
+	Level string `json:"level"`
+	// Format is text (the default) or json
+	Format string `json:"format"`
+	// Path is a file the log is appended to. The log is written to
+	// standard error when it is empty.
+	Path string `json:"path"`
+}
+
+// ToolConfig configures one tool. Not every tool accepts every setting.
+type ToolConfig struct {
+	// Enabled registers the tool; tools are enabled unless set to false
+	Enabled *bool `json:"enabled"`
+	// Description replaces the description shown to MCP clients
+	Description string `json:"description"`
+	// BaseURL overrides the base URL of the upstream service for this tool
+	// only (POTA tools). Its responses are not cached.
+	BaseURL string `json:"base_url"`
+	// CacheTTL limits how old a cached response this tool uses, as a
+	// duration; "0s" bypasses the cache (POTA tools)
+	CacheTTL string `json:"cache_ttl"`
+	// Limit caps the number of results: spots listed, map destinations or
+	// path waypoints
+	Limit int `json:"limit"`
+}
+
+// CacheConfig configures the cache of upstream responses
+type CacheConfig struct {
+	// Path is a file the cache is kept in across restarts. The cache is
+	// held in memory only when it is empty.
+	Path string `json:"path"`
+	// TTLs overrides the time to live of a source (callsign, park or
+	// spots) as a duration (e.g. "12h"); "0s" disables caching the source
+	TTLs map[string]string `json:"ttls"`
+}
+
+// UpstreamConfig configures requests to upstream services
+type UpstreamConfig struct {
+	// Timeout bounds each request attempt, as a duration (e.g. "10s")
+	Timeout string `json:"timeout"`
+	// HostTimeouts overrides Timeout for individual hosts
+	// (e.g. {"xmldata.qrz.com": "20s"})
+	HostTimeouts map[string]string `json:"host_timeouts"`
+	// Retries is the number of times a timed out or failed (5xx) request
+	// is retried. Defaults to 2; 0 disables retries.
+	Retries *int `json:"retries"`
+	// BaseURLs overrides the base URL of a service (callook, hamdb, qrz,
+	// hamqth, pota), e.g. to point at a local stand-in
+	BaseURLs map[string]string `json:"base_urls"`
+}
+
+// StationConfig describes your own station. Tools use it as the origin
+// when none is given.
+type StationConfig struct {
+	Callsign string `json:"callsign"`
+	// Grid and Latitude/Longitude give the station position. When neither
+	// is set the position is looked up from the callsign.
+	Grid         string   `json:"grid"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	LicenseClass string   `json:"license_class"`
+	// ITURegion is 1, 2 or 3
+	ITURegion int `json:"itu_region"`
+	// Units is imperial (the default) or metric
+	Units string `json:"units"`
+	// TimeZone is an IANA time zone name (e.g. America/New_York) used to
+	// show local times alongside UTC
+	TimeZone string `json:"time_zone"`
+}
+
+// DXCCConfig holds the location of the DXCC prefix table
+type DXCCConfig struct {
+	// Path is a cty.dat or cty.csv file from country-files.com
+	Path string `json:"path"`
+}
+
+// POTAConfig holds the location of the offline POTA park list and the
+// spot history
+type POTAConfig struct {
+	// ParksPath is the all_parks_ext.csv file from pota.app
+	ParksPath string            `json:"parks_path"`
+	History   SpotHistoryConfig `json:"history"`
+}
+
+// SpotHistoryConfig configures the background poller that keeps a history
+// of POTA spots
+type SpotHistoryConfig struct {
+	// Path is the file the spot history is kept in. Spots are only polled
+	// and the history tool only registered when it is set.
+	Path string `json:"path"`
+	// Interval is how often the current spots are polled, as a duration
+	// (default "2m")
+	Interval string `json:"interval"`
+	// Retention is how long spots are kept, as a duration (default "720h");
+	// "0s" keeps them until MaxSpots is reached
+	Retention string `json:"retention"`
+	// MaxSpots caps the number of spots kept, dropping the oldest first
+	// (default 100000); 0 removes the cap
+	MaxSpots *int `json:"max_spots"`
+}
+
+// CallsignConfig holds the callsign lookup configuration
+type CallsignConfig struct {
+	// Providers lists the lookup providers to try, in order
+	// (callook, uls, qrz, hamqth, hamdb). Defaults to callook only.
+	Providers []string         `json:"providers"`
+	QRZ       CredentialConfig `json:"qrz"`
+	HamQTH    CredentialConfig `json:"hamqth"`
+	ULS       ULSConfig        `json:"uls"`
+}
+
+// ULSConfig holds the location of the offline FCC ULS store
+type ULSConfig struct {
+	// Path is the store file written by uls-import
+	Path string `json:"path"`
+}
+
+// CredentialConfig holds the account credentials for a lookup provider
+type CredentialConfig struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Defaults of the server settings
+const (
+	// DefaultPort is the port the SSE and HTTP transports listen on unless configured
+	DefaultPort = 8080
+	// DefaultMetricsPath is where metrics are served unless configured
+	DefaultMetricsPath = "/metrics"
+)
+
+// DefaultCacheTTLs are the kinds of cached upstream responses (callsign
+// records, park details and the current spots) with how long each is kept
+// unless configured
+var DefaultCacheTTLs = map[string]time.Duration{
+	"callsign": 24 * time.Hour,
+	"park":     7 * 24 * time.Hour,
+	"spots":    30 * time.Second,
+}
+
+// Default returns the built-in configuration used for settings that are
+// not in the config file or the environment
+func Default() *Config {
+	config := &Config{}
+	config.Server.Port = DefaultPort
+	config.Server.MetricsPath = DefaultMetricsPath
+	config.Callsign.Providers = []string{"callook"}
+	return config
+}
+
+// Load returns the configuration from the config file, overridden by HAM_*
+// environment variables (see ApplyEnv), on top of the built-in defaults.
+// When configFile is empty only the defaults and the environment are used.
+// Every invalid setting is reported in the returned error.
 func Load(configFile string) (*Config, error) {
-	// Set default config file path if not provided
-	if configFile == "" {
-		configFile = "config.json"
+	config := Default()
+	var problems []string
+
+	if configFile != "" {
+		data, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		// Report keys that are not part of the configuration so that
+		// misspelled settings are not silently ignored
+		var raw any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+		problems = append(problems, unknownKeys("", raw, reflect.TypeOf(config).Elem())...)
+
+		// Values of the wrong type are skipped, and reported one by one
+		// since Unmarshal only returns the first
+		problems = append(problems, typeErrors("", raw, reflect.TypeOf(config).Elem())...)
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal(data, config); err != nil && !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+	}
+
+	problems = append(problems, ApplyEnv(config, os.Environ())...)
+
+	// Fall back to callook.info when no providers are configured
+	if len(config.Callsign.Providers) == 0 {
+		config.Callsign.Providers = []string{"callook"}
+	}
+
+	problems = append(problems, config.Validate()...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return config, nil
+}
+
+// unknownKeys returns the keys of a parsed JSON value that have no field in
+// the given type, as dotted paths
+func unknownKeys(path string, value any, t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedMapKeys(object) {
+			field, ok := jsonField(t, key)
+			if !ok {
+				unknown = append(unknown, fmt.Sprintf("%s: unknown key", joinPath(path, key)))
+				continue
+			}
+			unknown = append(unknown, unknownKeys(joinPath(path, key), object[key], field.Type)...)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedMapKeys(object) {
+			unknown = append(unknown, unknownKeys(joinPath(path, key), object[key], t.Elem())...)
+		}
+	}
+	return unknown
+}
+
+// typeErrors returns the values of a parsed JSON value that do not fit the
+// given type, as dotted paths
+func typeErrors(path string, value any, t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil {
+		return nil
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	var problems []string
+	object, isObject := value.(map[string]any)
+	switch {
+	case t.Kind() == reflect.Struct && isObject:
+		for _, key := range sortedMapKeys(object) {
+			if field, ok := jsonField(t, key); ok {
+				problems = append(problems, typeErrors(joinPath(path, key), object[key], field.Type)...)
+			}
+		}
+	case t.Kind() == reflect.Map && isObject:
+		for _, key := range sortedMapKeys(object) {
+			problems = append(problems, typeErrors(joinPath(path, key), object[key], t.Elem())...)
+		}
+	default:
+		// Decode the value on its own to find out whether it fits
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal(data, reflect.New(t).Interface()); errors.As(err, &typeErr) {
+			problems = append(problems, fmt.Sprintf("%s: expected %s, got %s", path, typeErr.Type, typeErr.Value))
+		}
 	}
+	return problems
+}
 
-	// Parse JSON
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+// jsonField returns the struct field with the given JSON name
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == name {
+			return field, true
+		}
 	}
+	return reflect.StructField{}, false
+}
 
-	return &config, nil
+// joinPath appends a key to a dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
 }
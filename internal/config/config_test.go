@@ -0,0 +1,194 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	config := Default()
+	config.Upstream.BaseURLs = map[string]string{"qrz": "http://qrz.local/"}
+	retries := 5
+	config.Upstream.Retries = &retries
+
+	problems := ApplyEnv(config, []string{
+		"PATH=/usr/bin",
+		"HAM_CONFIG=ignored.json",
+		"HAM_STATION_GRID=FN31pr",
+		"HAM_STATION_LATITUDE=41.7",
+		"HAM_STATION_ITU_REGION=2",
+		"HAM_SERVER_PORT=9090",
+		"HAM_CALLSIGN_PROVIDERS=qrz, callook,",
+		"HAM_CALLSIGN_QRZ_USERNAME=w1aw",
+		"HAM_UPSTREAM_BASE_URLS={\"pota\":\"http://pota.local/\"}",
+		"HAM_UPSTREAM_RETRIES=",
+		"HAM_TOOLS={\"pota-spots\":{\"limit\":5}}",
+		"HAM_STATION_GIRD=FN31",
+		"HAM_SERVER_PORT_NUMBER=1",
+		"HAM_STATION_LONGITUDE=west",
+	})
+
+	wantProblems := []string{
+		"HAM_SERVER_PORT_NUMBER: unknown environment variable",
+		"HAM_STATION_GIRD: unknown environment variable",
+		`HAM_STATION_LONGITUDE: expected a number, got "west"`,
+	}
+	if !reflect.DeepEqual(problems, wantProblems) {
+		t.Errorf("problems = %q, want %q", problems, wantProblems)
+	}
+
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"station.grid", config.Station.Grid, "FN31pr"},
+		{"station.latitude", *config.Station.Latitude, 41.7},
+		{"station.longitude", config.Station.Longitude, (*float64)(nil)},
+		{"station.itu_region", config.Station.ITURegion, 2},
+		{"server.port", config.Server.Port, 9090},
+		{"callsign.providers", config.Callsign.Providers, []string{"qrz", "callook"}},
+		{"callsign.qrz.username", config.Callsign.QRZ.Username, "w1aw"},
+		// Maps are merged into the configured map
+		{"upstream.base_urls", config.Upstream.BaseURLs, map[string]string{"qrz": "http://qrz.local/", "pota": "http://pota.local/"}},
+		// An empty value clears an optional setting
+		{"upstream.retries", config.Upstream.Retries, (*int)(nil)},
+		{"tools", config.Tools["pota-spots"].Limit, 5},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string
+	}{
+		{"defaults", func(*Config) {}, nil},
+		{"port", func(c *Config) { c.Server.Port = 0 }, []string{
+			"server.port: must be between 1 and 65535, got 0",
+		}},
+		{"metrics path", func(c *Config) { c.Server.MetricsPath = "/mcp" }, []string{
+			`server.metrics_path: must not be an MCP endpoint, got "/mcp"`,
+		}},
+		{"station", func(c *Config) {
+			lat := 91.0
+			c.Station.Grid = "ZZ99"
+			c.Station.Latitude = &lat
+			c.Station.ITURegion = 4
+		}, []string{
+			`station.grid: invalid grid locator: "ZZ99": unexpected character 'Z'`,
+			"station.latitude: must be given together with station.longitude",
+			"station.latitude: must be between -90 and 90, got 91",
+			"station.itu_region: must be 1, 2 or 3, got 4",
+		}},
+		{"providers", func(c *Config) { c.Callsign.Providers = []string{"uls", "qrz", "fcc"} }, []string{
+			"callsign.uls.path: must be given for the uls provider",
+			"callsign.qrz: a username and password must be given for the qrz provider",
+			`callsign.providers: unknown provider "fcc" (expected one of callook, uls, qrz, hamqth, hamdb)`,
+		}},
+		{"upstream", func(c *Config) {
+			retries := -1
+			c.Upstream.Timeout = "10"
+			c.Upstream.Retries = &retries
+			c.Upstream.BaseURLs = map[string]string{"pota": "pota.local", "callbook": "http://x/"}
+		}, []string{
+			`upstream.timeout: invalid duration "10" (e.g. 30s, 10m, 24h)`,
+			"upstream.retries: must not be negative",
+			`upstream.base_urls: unknown key "callbook" (expected one of callook, hamdb, qrz, hamqth, pota)`,
+			`upstream.base_urls.pota: invalid URL "pota.local"`,
+		}},
+		{"cache", func(c *Config) { c.Cache.TTLs = map[string]string{"parks": "1h", "spots": "-1s"} }, []string{
+			`cache.ttls: unknown key "parks" (expected one of callsign, park, spots)`,
+			"cache.ttls.spots: must not be negative",
+		}},
+		{"spot history", func(c *Config) {
+			maxSpots := -1
+			c.POTA.History = SpotHistoryConfig{Interval: "0s", Retention: "forever", MaxSpots: &maxSpots}
+		}, []string{
+			"pota.history.interval: must be greater than zero",
+			`pota.history.retention: invalid duration "forever" (e.g. 30s, 10m, 24h)`,
+			"pota.history.max_spots: must not be negative",
+		}},
+		{"tools", func(c *Config) {
+			c.Tools = map[string]ToolConfig{
+				"pota-spot":        {},
+				"sun-times":        {Limit: 3},
+				"path-profile":     {Limit: 1},
+				"pota-spots":       {BaseURL: "http://pota.local/", CacheTTL: "1m", Limit: 10},
+				"pota-park-lookup": {CacheTTL: "1 day"},
+				"bearing-map":      {Limit: 20},
+			}
+		}, []string{
+			"tools.path-profile.limit: must be at least 2",
+			`tools.pota-park-lookup.cache_ttl: invalid duration "1 day" (e.g. 30s, 10m, 24h)`,
+			"tools.pota-spot: unknown tool",
+			"tools.pota-spots.cache_ttl: cannot be combined with base_url, since a tool with its own base URL is not cached",
+			"tools.sun-times.limit: not accepted by this tool",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			tt.modify(config)
+			if got := config.Validate(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"server": {"port": 70000}, "station": {"gird": "FN31"}, "tools": {"sun-times": {"limt": 3}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HAM_STATION_UNITS", "furlongs")
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("Load succeeded, want an error")
+	}
+	for _, want := range []string{
+		"station.gird: unknown key",
+		"tools.sun-times.limt: unknown key",
+		`station.units: must be imperial or metric, got "furlongs"`,
+		"server.port: must be between 1 and 65535, got 70000",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error %q does not report %q", err, want)
+		}
+	}
+}
+
+func TestLoadReportsEveryTypeError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"server": {"port": "x"}, "station": {"latitude": "y", "grid": "FN31pr"}, "callsign": {"providers": ["callook", 1]}, "tools": {"pota-spots": {"limit": "ten"}}, "log": 3}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("Load succeeded, want an error")
+	}
+	for _, want := range []string{
+		"server.port: expected int, got string",
+		"station.latitude: expected float64, got string",
+		"callsign.providers: expected string, got number",
+		"tools.pota-spots.limit: expected int, got string",
+		"log: expected config.LogConfig, got number",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load error %q does not report %q", err, want)
+		}
+	}
+}
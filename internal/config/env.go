@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the names of environment variables that override settings
+const EnvPrefix = "HAM_"
+
+// ConfigEnv names the environment variable holding the config file path
+const ConfigEnv = "HAM_CONFIG"
+
+// ApplyEnv overrides settings from HAM_* environment variables, given as
+// KEY=VALUE pairs. Each setting has a variable named after its path in the
+// config file, e.g. HAM_STATION_GRID for station.grid and
+// HAM_CALLSIGN_QRZ_USERNAME for callsign.qrz.username. Lists are
+// comma-separated and maps are JSON objects, whose keys are merged into the
+// configured map. It returns a problem for each variable that names no
+// setting or cannot be applied.
+func ApplyEnv(config *Config, environ []string) []string {
+	fields := make(map[string]reflect.Value)
+	envFields(strings.TrimSuffix(EnvPrefix, "_"), reflect.ValueOf(config).Elem(), fields)
+
+	environ = append([]string(nil), environ...)
+	sort.Strings(environ)
+
+	var problems []string
+	for _, pair := range environ {
+		name, value, _ := strings.Cut(pair, "=")
+		if !strings.HasPrefix(name, EnvPrefix) || name == ConfigEnv {
+			continue
+		}
+		field, ok := fields[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unknown environment variable", name))
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return problems
+}
+
+// envFields maps the environment variable name of every setting in a struct
+// to its field
+func envFields(prefix string, v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			envFields(name, field, fields)
+		} else {
+			fields[name] = field
+		}
+	}
+}
+
+// setField parses an environment variable value into a setting. An empty
+// value clears optional settings.
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.Pointer:
+		if value == "" {
+			field.SetZero()
+			return nil
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := setField(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		if err := json.Unmarshal([]byte(value), field.Addr().Interface()); err != nil {
+			return fmt.Errorf("expected a JSON object: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
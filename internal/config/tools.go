@@ -0,0 +1,73 @@
+package config
+
+import "slices"
+
+// Per-tool settings beyond enabled and description
+const (
+	settingBaseURL  = "base_url"
+	settingCacheTTL = "cache_ttl"
+	settingLimit    = "limit"
+)
+
+// toolSettings lists every tool by name with the per-tool settings it accepts
+var toolSettings = map[string][]string{
+	"callsign-lookup":   nil,
+	"antenna-bearing":   nil,
+	"grid-distance":     nil,
+	"path-profile":      {settingLimit},
+	"callsign-bearing":  nil,
+	"bearing-map":       {settingLimit},
+	"sun-times":         nil,
+	"band-info":         nil,
+	"callsign-entity":   nil,
+	"pota-park-lookup":  {settingBaseURL, settingCacheTTL},
+	"pota-spots":        {settingBaseURL, settingCacheTTL, settingLimit},
+	"pota-park-search":  {settingLimit},
+	"pota-parks-nearby": {settingLimit},
+	"pota-spot-history": {settingLimit},
+	"cache-stats":       nil,
+	"cache-clear":       nil,
+}
+
+// minToolLimits is the smallest limit a tool accepts, where it is above one
+var minToolLimits = map[string]int{
+	"path-profile": 2,
+}
+
+// validateTools checks the tools section, reporting unknown tools and
+// settings that a tool does not accept
+func validateTools(v *validator, tools map[string]ToolConfig) {
+	for _, name := range sortedMapKeys(tools) {
+		tool := tools[name]
+		path := "tools." + name
+		accepted, ok := toolSettings[name]
+		if !ok {
+			v.add(path, "unknown tool")
+			continue
+		}
+
+		given := map[string]bool{
+			settingBaseURL:  tool.BaseURL != "",
+			settingCacheTTL: tool.CacheTTL != "",
+			settingLimit:    tool.Limit != 0,
+		}
+		for _, setting := range []string{settingBaseURL, settingCacheTTL, settingLimit} {
+			if given[setting] && !slices.Contains(accepted, setting) {
+				v.add(path+"."+setting, "not accepted by this tool")
+			}
+		}
+
+		if tool.BaseURL != "" {
+			v.url(path+".base_url", tool.BaseURL)
+		}
+		if tool.CacheTTL != "" {
+			v.duration(path+".cache_ttl", tool.CacheTTL)
+			if tool.BaseURL != "" {
+				v.add(path+".cache_ttl", "cannot be combined with base_url, since a tool with its own base URL is not cached")
+			}
+		}
+		if minimum := max(1, minToolLimits[name]); tool.Limit != 0 && tool.Limit < minimum {
+			v.add(path+".limit", "must be at least %d", minimum)
+		}
+	}
+}
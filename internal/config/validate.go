@@ -0,0 +1,204 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+)
+
+// Names accepted in lists and map keys of the configuration
+var (
+	// Providers are the callsign lookup providers
+	Providers = []string{"callook", "uls", "qrz", "hamqth", "hamdb"}
+	// Services are the upstream services whose base URL can be overridden
+	Services = []string{"callook", "hamdb", "qrz", "hamqth", "pota"}
+)
+
+// Validate checks every setting and returns a problem for each invalid one,
+// prefixed with its path in the config file
+func (c *Config) Validate() []string {
+	v := &validator{}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		v.add("server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	switch path := c.Server.MetricsPath; {
+	case path == "":
+	case !strings.HasPrefix(path, "/"):
+		v.add("server.metrics_path", "must start with /, got %q", path)
+	case path == "/" || path == "/mcp" || path == "/sse" || path == "/message":
+		v.add("server.metrics_path", "must not be an MCP endpoint, got %q", path)
+	}
+	if c.Server.ShutdownGrace != "" {
+		v.duration("server.shutdown_grace", c.Server.ShutdownGrace)
+	}
+
+	c.Station.validate(v)
+	c.Callsign.validate(v)
+	c.Upstream.validate(v)
+	c.POTA.History.validate(v)
+	c.Cache.validate(v)
+
+	switch strings.ToLower(c.Log.Level) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		v.add("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if c.Log.Format != "" && c.Log.Format != "text" && c.Log.Format != "json" {
+		v.add("log.format", "must be text or json, got %q", c.Log.Format)
+	}
+
+	validateTools(v, c.Tools)
+
+	return v.problems
+}
+
+// validate checks the station profile
+func (s *StationConfig) validate(v *validator) {
+	if s.Callsign != "" {
+		if _, err := callsign.Parse(s.Callsign); err != nil {
+			v.add("station.callsign", "%v", err)
+		}
+	}
+	if s.Grid != "" {
+		if _, err := maidenhead.Parse(s.Grid); err != nil {
+			v.add("station.grid", "%v", err)
+		}
+	}
+	if (s.Latitude == nil) != (s.Longitude == nil) {
+		v.add("station.latitude", "must be given together with station.longitude")
+	}
+	if s.Latitude != nil && (*s.Latitude < -90 || *s.Latitude > 90) {
+		v.add("station.latitude", "must be between -90 and 90, got %g", *s.Latitude)
+	}
+	if s.Longitude != nil && (*s.Longitude < -180 || *s.Longitude > 180) {
+		v.add("station.longitude", "must be between -180 and 180, got %g", *s.Longitude)
+	}
+	if s.ITURegion < 0 || s.ITURegion > 3 {
+		v.add("station.itu_region", "must be 1, 2 or 3, got %d", s.ITURegion)
+	}
+	if s.Units != "" && s.Units != "imperial" && s.Units != "metric" {
+		v.add("station.units", "must be imperial or metric, got %q", s.Units)
+	}
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			v.add("station.time_zone", "unknown time zone %q", s.TimeZone)
+		}
+	}
+}
+
+// validate checks the callsign providers and their credentials
+func (c *CallsignConfig) validate(v *validator) {
+	for _, name := range c.Providers {
+		switch strings.ToLower(name) {
+		case "uls":
+			if c.ULS.Path == "" {
+				v.add("callsign.uls.path", "must be given for the uls provider")
+			}
+		case "qrz":
+			if c.QRZ.Username == "" || c.QRZ.Password == "" {
+				v.add("callsign.qrz", "a username and password must be given for the qrz provider")
+			}
+		case "hamqth":
+			if c.HamQTH.Username == "" || c.HamQTH.Password == "" {
+				v.add("callsign.hamqth", "a username and password must be given for the hamqth provider")
+			}
+		case "callook", "hamdb":
+		default:
+			v.add("callsign.providers", "unknown provider %q (expected one of %s)", name, strings.Join(Providers, ", "))
+		}
+	}
+}
+
+// validate checks the spot history settings
+func (h *SpotHistoryConfig) validate(v *validator) {
+	if h.Interval != "" {
+		v.duration("pota.history.interval", h.Interval)
+		if d, err := time.ParseDuration(h.Interval); err == nil && d == 0 {
+			v.add("pota.history.interval", "must be greater than zero")
+		}
+	}
+	if h.Retention != "" {
+		v.duration("pota.history.retention", h.Retention)
+	}
+	if h.MaxSpots != nil && *h.MaxSpots < 0 {
+		v.add("pota.history.max_spots", "must not be negative")
+	}
+}
+
+// validate checks the upstream request settings
+func (u *UpstreamConfig) validate(v *validator) {
+	if u.Timeout != "" {
+		v.duration("upstream.timeout", u.Timeout)
+	}
+	for _, host := range sortedMapKeys(u.HostTimeouts) {
+		v.duration("upstream.host_timeouts."+host, u.HostTimeouts[host])
+	}
+	if u.Retries != nil && *u.Retries < 0 {
+		v.add("upstream.retries", "must not be negative")
+	}
+	for _, name := range sortedMapKeys(u.BaseURLs) {
+		v.oneOf("upstream.base_urls", name, Services)
+		v.url("upstream.base_urls."+name, u.BaseURLs[name])
+	}
+}
+
+// validate checks the cache settings
+func (c *CacheConfig) validate(v *validator) {
+	for _, source := range sortedMapKeys(c.TTLs) {
+		v.oneOf("cache.ttls", source, sortedMapKeys(DefaultCacheTTLs))
+		v.duration("cache.ttls."+source, c.TTLs[source])
+	}
+}
+
+// validator collects the problems found in a configuration
+type validator struct {
+	problems []string
+}
+
+// add records a problem with a setting
+func (v *validator) add(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+// duration checks that a setting is a non-negative duration
+func (v *validator) duration(path, value string) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		v.add(path, "invalid duration %q (e.g. 30s, 10m, 24h)", value)
+	} else if d < 0 {
+		v.add(path, "must not be negative")
+	}
+}
+
+// url checks that a setting is an absolute URL
+func (v *validator) url(path, value string) {
+	if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+		v.add(path, "invalid URL %q", value)
+	}
+}
+
+// oneOf checks that a map key is one of the known names
+func (v *validator) oneOf(path, name string, known []string) {
+	for _, k := range known {
+		if name == k {
+			return
+		}
+	}
+	v.add(path, "unknown key %q (expected one of %s)", name, strings.Join(known, ", "))
+}
+
+// sortedMapKeys returns the keys of a map in order
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
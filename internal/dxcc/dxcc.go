@@ -0,0 +1,216 @@
+// Package dxcc resolves callsigns to DXCC entities using the prefix tables
+// published at country-files.com in the cty.dat and cty.csv (Big CTY) formats
+package dxcc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+)
+
+// Entity is a DXCC entity (country) with its default zones and centroid
+type Entity struct {
+	Name          string  `json:"name"`
+	PrimaryPrefix string  `json:"primaryPrefix"`
+	DXCC          int     `json:"dxcc,omitempty"`
+	Continent     string  `json:"continent"`
+	CQZone        int     `json:"cqZone"`
+	ITUZone       int     `json:"ituZone"`
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+	UTCOffset     float64 `json:"utcOffset"`
+	// WAEOnly marks entities that only count for the WAE/CQ lists, shown
+	// with a leading '*' in cty.dat
+	WAEOnly bool `json:"waeOnly,omitempty"`
+}
+
+// Match is the result of resolving a callsign. Zone, continent and location
+// overrides attached to the matching prefix are already applied.
+type Match struct {
+	Entity    *Entity `json:"entity"`
+	Prefix    string  `json:"prefix"`
+	Exact     bool    `json:"exact"`
+	Continent string  `json:"continent"`
+	CQZone    int     `json:"cqZone"`
+	ITUZone   int     `json:"ituZone"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	UTCOffset float64 `json:"utcOffset"`
+}
+
+// Database holds the prefix and exact-callsign tables
+type Database struct {
+	entities  []*Entity
+	prefixes  map[string]*Match
+	exact     map[string]*Match
+	maxPrefix int
+}
+
+// Load reads a cty.dat or cty.csv file, choosing the parser by extension
+func Load(path string) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DXCC prefix file: %w", err)
+	}
+	defer file.Close()
+
+	var db *Database
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		db, err = ParseCSV(file)
+	} else {
+		db, err = ParseDat(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DXCC prefix file %s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+// newDatabase creates an empty database
+func newDatabase() *Database {
+	return &Database{
+		prefixes: make(map[string]*Match),
+		exact:    make(map[string]*Match),
+	}
+}
+
+// Entities returns all entities in file order
+func (db *Database) Entities() []*Entity {
+	return db.entities
+}
+
+// Lookup resolves a callsign or prefix to its entity. Exact callsign entries
+// take precedence over the longest matching prefix. Callsigns with a prefix
+// designator or location suffix resolve by their operating prefix, and
+// maritime or aeronautical mobile stations have no entity.
+func (db *Database) Lookup(call string) (*Match, bool) {
+	key := strings.ToUpper(strings.TrimSpace(call))
+	if key == "" {
+		return nil, false
+	}
+
+	if match, ok := db.exact[key]; ok {
+		return match, true
+	}
+
+	if parsed, err := callsign.Parse(key); err == nil {
+		switch {
+		case parsed.IsMaritimeMobile() || parsed.IsAeronauticalMobile():
+			return nil, false
+		case parsed.IsAway():
+			key = parsed.OperatingPrefix()
+		default:
+			if match, ok := db.exact[parsed.Base]; ok {
+				return match, true
+			}
+			key = parsed.Base
+		}
+	}
+
+	for n := min(len(key), db.maxPrefix); n > 0; n-- {
+		if match, ok := db.prefixes[key[:n]]; ok {
+			return match, true
+		}
+	}
+
+	return nil, false
+}
+
+// addEntity registers an entity
+func (db *Database) addEntity(entity *Entity) {
+	db.entities = append(db.entities, entity)
+}
+
+// addAlias registers a prefix or exact callsign entry for an entity. The
+// alias may carry cty overrides: (CQ zone), [ITU zone], <lat/lon>,
+// {continent} and ~UTC offset~.
+func (db *Database) addAlias(entity *Entity, alias string) error {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return nil
+	}
+
+	exact := strings.HasPrefix(alias, "=")
+	alias = strings.TrimPrefix(alias, "=")
+
+	end := strings.IndexAny(alias, "([<{~")
+	if end < 0 {
+		end = len(alias)
+	}
+	prefix := strings.ToUpper(alias[:end])
+	if prefix == "" {
+		return fmt.Errorf("empty prefix in %q", alias)
+	}
+
+	match := &Match{
+		Entity:    entity,
+		Prefix:    prefix,
+		Exact:     exact,
+		Continent: entity.Continent,
+		CQZone:    entity.CQZone,
+		ITUZone:   entity.ITUZone,
+		Latitude:  entity.Latitude,
+		Longitude: entity.Longitude,
+		UTCOffset: entity.UTCOffset,
+	}
+	if err := applyOverrides(match, alias[end:]); err != nil {
+		return fmt.Errorf("invalid override in %q: %w", alias, err)
+	}
+
+	if exact {
+		db.exact[prefix] = match
+	} else {
+		db.prefixes[prefix] = match
+		db.maxPrefix = max(db.maxPrefix, len(prefix))
+	}
+
+	return nil
+}
+
+// applyOverrides parses the override suffix of a cty alias into the match
+func applyOverrides(match *Match, overrides string) error {
+	closers := map[byte]byte{'(': ')', '[': ']', '<': '>', '{': '}', '~': '~'}
+
+	for overrides != "" {
+		open := overrides[0]
+		closer, ok := closers[open]
+		if !ok {
+			return fmt.Errorf("unexpected %q", overrides)
+		}
+		end := strings.IndexByte(overrides[1:], closer)
+		if end < 0 {
+			return fmt.Errorf("unterminated %q", overrides)
+		}
+		value := overrides[1 : end+1]
+		overrides = overrides[end+2:]
+
+		var err error
+		switch open {
+		case '(':
+			_, err = fmt.Sscanf(value, "%d", &match.CQZone)
+		case '[':
+			_, err = fmt.Sscanf(value, "%d", &match.ITUZone)
+		case '<':
+			var lat, westLon float64
+			if _, err = fmt.Sscanf(value, "%f/%f", &lat, &westLon); err == nil {
+				match.Latitude, match.Longitude = lat, -westLon
+			}
+		case '{':
+			match.Continent = value
+		case '~':
+			var westOffset float64
+			if _, err = fmt.Sscanf(value, "%f", &westOffset); err == nil {
+				match.UTCOffset = -westOffset
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
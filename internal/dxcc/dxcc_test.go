@@ -0,0 +1,131 @@
+package dxcc
+
+import (
+	"strings"
+	"testing"
+)
+
+// testDat is a cut-down cty.dat with the override forms used upstream
+const testDat = `United States:            05:  08:  NA:   37.53:    91.67:     5.0:  K:
+    AA,AB,K,N,W,KH6(31)[61]{OC}<21.12/157.48>~10.0~,
+    =W1AW(5)[8],=N6DX/M;
+Malta:                    15:  28:  EU:   35.88:   -14.50:    -1.0:  9H:
+    9H;
+Sov Mil Order of Malta:   15:  28:  EU:   41.90:   -12.43:    -1.0:  *1A:
+    1A;
+`
+
+// testCSV holds the same entities in the cty.csv format
+const testCSV = `K,United States,291,NA,5,8,37.53,91.67,5.0,AA AB K N W KH6(31)[61]{OC}<21.12/157.48>~10.0~ =W1AW(5)[8];
+9H,Malta,257,EU,15,28,35.88,-14.50,-1.0,9H;
+`
+
+func TestParse(t *testing.T) {
+	parsers := []struct {
+		name  string
+		parse func(string) (*Database, error)
+		input string
+	}{
+		{"dat", parseDat, testDat},
+		{"csv", parseCSV, testCSV},
+	}
+
+	tests := []struct {
+		call      string
+		entity    string
+		prefix    string
+		exact     bool
+		continent string
+		cq, itu   int
+		lat, lon  float64
+		utc       float64
+	}{
+		// Longitudes and UTC offsets are west positive in the file
+		{"K1ABC", "United States", "K", false, "NA", 5, 8, 37.53, -91.67, -5},
+		{"9H1XX", "Malta", "9H", false, "EU", 15, 28, 35.88, 14.50, 1},
+		// Overrides on a prefix replace the entity defaults
+		{"KH6XX", "United States", "KH6", false, "OC", 31, 61, 21.12, -157.48, -10},
+		// Exact callsigns win over prefixes and keep their own overrides
+		{"W1AW", "United States", "W1AW", true, "NA", 5, 8, 37.53, -91.67, -5},
+		{"w1aw/7", "United States", "W", false, "NA", 5, 8, 37.53, -91.67, -5},
+		// Prefix designators resolve by the operating prefix
+		{"9H/K1ABC", "Malta", "9H", false, "EU", 15, 28, 35.88, 14.50, 1},
+		{"K1ABC/P", "United States", "K", false, "NA", 5, 8, 37.53, -91.67, -5},
+	}
+
+	for _, p := range parsers {
+		t.Run(p.name, func(t *testing.T) {
+			db, err := p.parse(p.input)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			for _, tt := range tests {
+				match, ok := db.Lookup(tt.call)
+				if !ok {
+					t.Errorf("Lookup(%s) found nothing", tt.call)
+					continue
+				}
+				got := []any{match.Entity.Name, match.Prefix, match.Exact, match.Continent, match.CQZone, match.ITUZone, match.Latitude, match.Longitude, match.UTCOffset}
+				want := []any{tt.entity, tt.prefix, tt.exact, tt.continent, tt.cq, tt.itu, tt.lat, tt.lon, tt.utc}
+				for i := range got {
+					if got[i] != want[i] {
+						t.Errorf("Lookup(%s) = %v, want %v", tt.call, got, want)
+						break
+					}
+				}
+			}
+
+			for _, call := range []string{"", "ZZ1ZZ", "K1ABC/MM", "K1ABC/AM"} {
+				if match, ok := db.Lookup(call); ok {
+					t.Errorf("Lookup(%q) = %s, want no entity", call, match.Entity.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDatEntities(t *testing.T) {
+	db, err := parseDat(testDat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entities := db.Entities()
+	if len(entities) != 3 {
+		t.Fatalf("got %d entities, want 3", len(entities))
+	}
+	if e := entities[2]; e.PrimaryPrefix != "1A" || !e.WAEOnly {
+		t.Errorf("WAE entity = %s %v, want 1A marked WAE only", e.PrimaryPrefix, e.WAEOnly)
+	}
+	if e := entities[0]; e.UTCOffset != -5 || e.Longitude != -91.67 {
+		t.Errorf("United States offset %v lon %v, want -5 and -91.67", e.UTCOffset, e.Longitude)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (*Database, error)
+		input string
+	}{
+		{"dat empty", parseDat, ""},
+		{"dat short header", parseDat, "Malta: 15: 28: EU:\n  9H;\n"},
+		{"dat bad zone", parseDat, "Malta: xx: 28: EU: 35.88: -14.50: -1.0: 9H:\n  9H;\n"},
+		{"dat unterminated", parseDat, "Malta: 15: 28: EU: 35.88: -14.50: -1.0: 9H:\n  9H,\n"},
+		{"dat bad override", parseDat, "Malta: 15: 28: EU: 35.88: -14.50: -1.0: 9H:\n  9H(15;\n"},
+		{"dat bad offset override", parseDat, "Malta: 15: 28: EU: 35.88: -14.50: -1.0: 9H:\n  9H~x~;\n"},
+		{"csv empty", parseCSV, ""},
+		{"csv short line", parseCSV, "9H,Malta,257,EU\n"},
+		{"csv bad dxcc", parseCSV, "9H,Malta,x,EU,15,28,35.88,-14.50,-1.0,9H;\n"},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.parse(tt.input); err == nil {
+			t.Errorf("%s: parse succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func parseDat(input string) (*Database, error) { return ParseDat(strings.NewReader(input)) }
+
+func parseCSV(input string) (*Database, error) { return ParseCSV(strings.NewReader(input)) }
@@ -0,0 +1,167 @@
+package dxcc
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseDat parses the cty.dat format. Each entity starts with a header line
+//
+//	Name: CQ: ITU: Cont: Lat: Lon: UTC offset: Primary prefix:
+//
+// followed by comma-separated aliases on indented lines ending with ';'.
+// Longitudes and UTC offsets are given with west positive and are converted
+// to east positive.
+func ParseDat(r io.Reader) (*Database, error) {
+	db := newDatabase()
+	scanner := bufio.NewScanner(r)
+
+	var entity *Entity
+	var aliases strings.Builder
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if entity == nil {
+			var err error
+			if entity, err = parseDatHeader(line); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			db.addEntity(entity)
+			aliases.Reset()
+			continue
+		}
+
+		aliases.WriteString(strings.TrimSpace(line))
+		if strings.HasSuffix(aliases.String(), ";") {
+			list := strings.TrimSuffix(aliases.String(), ";")
+			for _, alias := range strings.Split(list, ",") {
+				if err := db.addAlias(entity, alias); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNum, err)
+				}
+			}
+			entity = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if entity != nil {
+		return nil, fmt.Errorf("unterminated prefix list for %s", entity.Name)
+	}
+	if len(db.entities) == 0 {
+		return nil, errors.New("no entities found")
+	}
+
+	return db, nil
+}
+
+// parseDatHeader parses a cty.dat entity header line
+func parseDatHeader(line string) (*Entity, error) {
+	fields := strings.Split(line, ":")
+	if len(fields) < 8 {
+		return nil, fmt.Errorf("malformed entity header %q", line)
+	}
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	entity, err := newEntity(fields[0], fields[7], fields[3], fields[1], fields[2], fields[4], fields[5], fields[6])
+	if err != nil {
+		return nil, fmt.Errorf("entity %s: %w", fields[0], err)
+	}
+
+	return entity, nil
+}
+
+// ParseCSV parses the cty.csv format, one entity per line:
+//
+//	Primary prefix,Name,DXCC,Cont,CQ,ITU,Lat,Lon,UTC offset,Aliases;
+//
+// where aliases are space-separated. Longitudes and UTC offsets are west
+// positive.
+func ParseCSV(r io.Reader) (*Database, error) {
+	db := newDatabase()
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) < 10 {
+			return nil, fmt.Errorf("malformed entity line %q", strings.Join(row, ","))
+		}
+
+		entity, err := newEntity(row[1], row[0], row[3], row[4], row[5], row[6], row[7], row[8])
+		if err != nil {
+			return nil, fmt.Errorf("entity %s: %w", row[1], err)
+		}
+		if entity.DXCC, err = strconv.Atoi(strings.TrimSpace(row[2])); err != nil {
+			return nil, fmt.Errorf("entity %s: invalid DXCC number: %w", row[1], err)
+		}
+		db.addEntity(entity)
+
+		for _, alias := range strings.Fields(strings.TrimSuffix(strings.TrimSpace(row[9]), ";")) {
+			if err := db.addAlias(entity, alias); err != nil {
+				return nil, fmt.Errorf("entity %s: %w", entity.Name, err)
+			}
+		}
+	}
+	if len(db.entities) == 0 {
+		return nil, errors.New("no entities found")
+	}
+
+	return db, nil
+}
+
+// newEntity builds an entity from the text fields shared by both formats
+func newEntity(name, prefix, continent, cq, itu, lat, westLon, offset string) (*Entity, error) {
+	entity := &Entity{
+		Name:      strings.TrimSpace(name),
+		Continent: strings.TrimSpace(continent),
+	}
+
+	prefix = strings.TrimSpace(prefix)
+	if strings.HasPrefix(prefix, "*") {
+		entity.WAEOnly = true
+		prefix = prefix[1:]
+	}
+	entity.PrimaryPrefix = prefix
+
+	var err error
+	if entity.CQZone, err = strconv.Atoi(strings.TrimSpace(cq)); err != nil {
+		return nil, fmt.Errorf("invalid CQ zone: %w", err)
+	}
+	if entity.ITUZone, err = strconv.Atoi(strings.TrimSpace(itu)); err != nil {
+		return nil, fmt.Errorf("invalid ITU zone: %w", err)
+	}
+	if entity.Latitude, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err != nil {
+		return nil, fmt.Errorf("invalid latitude: %w", err)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(westLon), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude: %w", err)
+	}
+	entity.Longitude = -lon
+	westOffset, err := strconv.ParseFloat(strings.TrimSpace(offset), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UTC offset: %w", err)
+	}
+	entity.UTCOffset = -westOffset
+
+	return entity, nil
+}
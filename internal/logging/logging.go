@@ -0,0 +1,70 @@
+// Package logging sets up the structured logger. Records are written to
+// standard error or a file, never to standard output, which carries the MCP
+// protocol when serving over stdio.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/config"
+)
+
+// Formats accepted in the log configuration
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel parses a log level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", name)
+	}
+}
+
+// New creates a logger from the configuration. The returned function closes
+// the log file, if any.
+func New(cfg config.LogConfig) (*slog.Logger, func() error, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out io.Writer = os.Stderr
+	closeLog := func() error { return nil }
+	if cfg.Path != "" {
+		file, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening log file: %v", err)
+		}
+		out = file
+		closeLog = file.Close
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch cfg.Format {
+	case "", FormatText:
+		handler = slog.NewTextHandler(out, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		closeLog()
+		return nil, nil, fmt.Errorf("unknown log format %q (expected %s or %s)", cfg.Format, FormatText, FormatJSON)
+	}
+
+	return slog.New(handler), closeLog, nil
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// loggerName identifies this application in MCP log notifications
+const loggerName = "ham-radio-assistant"
+
+// MCPHandler passes records to another handler and also forwards records
+// logged with the context of a client request to that client as MCP
+// logging notifications, at the level the client asked for
+type MCPHandler struct {
+	next   slog.Handler
+	server *server.MCPServer
+	attrs  []slog.Attr
+	group  string
+}
+
+// NewMCPHandler wraps a handler so that records are also sent to clients
+// of the MCP server
+func NewMCPHandler(next slog.Handler, s *server.MCPServer) *MCPHandler {
+	return &MCPHandler{next: next, server: s}
+}
+
+// Enabled reports whether the wrapped handler or the requesting client
+// wants records at the level
+func (h *MCPHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.next.Enabled(ctx, level) {
+		return true
+	}
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithLogging)
+	return ok && mcpLevel(level).ShouldSendTo(session.GetLogLevel())
+}
+
+// Handle writes the record and forwards it to the requesting client
+func (h *MCPHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.next.Enabled(ctx, r.Level) {
+		err = h.next.Handle(ctx, r)
+	}
+
+	if session := server.ClientSessionFromContext(ctx); session != nil && session.Initialized() {
+		data := map[string]any{"message": r.Message}
+		for _, attr := range h.attrs {
+			data[attr.Key] = value(attr.Value)
+		}
+		r.Attrs(func(attr slog.Attr) bool {
+			data[h.key(attr.Key)] = value(attr.Value)
+			return true
+		})
+		// Clients that do not support logging are not an error
+		_ = h.server.SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(mcpLevel(r.Level), loggerName, data))
+	}
+
+	return err
+}
+
+// WithAttrs returns a handler that adds the attributes to every record
+func (h *MCPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.key(attr.Key), Value: attr.Value.Resolve()})
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies later attributes with the group
+func (h *MCPHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.group = h.key(name)
+	return &clone
+}
+
+// key qualifies an attribute key with the current group
+func (h *MCPHandler) key(key string) string {
+	if h.group == "" {
+		return key
+	}
+	return h.group + "." + key
+}
+
+// value converts an attribute value for a JSON notification, writing
+// durations and times as text
+func value(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339)
+	case slog.KindGroup:
+		group := make(map[string]any)
+		for _, attr := range v.Group() {
+			group[attr.Key] = value(attr.Value)
+		}
+		return group
+	default:
+		return v.Any()
+	}
+}
+
+// mcpLevel converts a log level to the MCP logging level
+func mcpLevel(level slog.Level) mcp.LoggingLevel {
+	switch {
+	case level >= slog.LevelError:
+		return mcp.LoggingLevelError
+	case level >= slog.LevelWarn:
+		return mcp.LoggingLevelWarning
+	case level >= slog.LevelInfo:
+		return mcp.LoggingLevelInfo
+	default:
+		return mcp.LoggingLevelDebug
+	}
+}
@@ -0,0 +1,37 @@
+package lookup
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/cache"
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// CacheSource is the cache source holding callsign records
+const CacheSource = "callsign"
+
+// Cached answers lookups from a cache before falling back to a provider
+type Cached struct {
+	provider CallsignProvider
+	cache    *cache.Cache
+}
+
+// NewCached wraps a provider with a cache. Only found records are cached,
+// so a callsign that was not found is looked up again next time.
+func NewCached(provider CallsignProvider, c *cache.Cache) *Cached {
+	return &Cached{provider: provider, cache: c}
+}
+
+// Name returns the name of the wrapped provider
+func (c *Cached) Name() string {
+	return c.provider.Name()
+}
+
+// Lookup returns the cached record for the callsign, looking it up from the
+// wrapped provider when it is not cached
+func (c *Cached) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	return cache.Get(ctx, c.cache, CacheSource, strings.ToUpper(callsign), func(ctx context.Context) (*models.CallsignRecord, error) {
+		return c.provider.Lookup(ctx, callsign)
+	})
+}
@@ -0,0 +1,103 @@
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+const (
+	callookBaseURL = "https://callook.info/"
+)
+
+// Callook looks up US callsigns using the callook.info API
+type Callook struct {
+	client  *upstream.Client
+	baseURL string
+}
+
+// NewCallook creates a callook.info provider. An empty baseURL selects the
+// public service.
+func NewCallook(client *upstream.Client, baseURL string) *Callook {
+	if baseURL == "" {
+		baseURL = callookBaseURL
+	}
+	return &Callook{client: client, baseURL: baseURL}
+}
+
+// Name returns the provider name
+func (c *Callook) Name() string {
+	return "callook"
+}
+
+// Lookup looks up a callsign using the callook.info API
+func (c *Callook) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	// Make API request to callook.info. The base URL may or may not end in
+	// a slash.
+	apiURL, err := url.JoinPath(c.baseURL, url.PathEscape(callsign), "json")
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making API request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK response status: %s", resp.Status)
+	}
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	// Decode JSON response
+	var result models.CallsignResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+
+	if result.Status != "VALID" {
+		return nil, ErrNotFound
+	}
+
+	return callookRecord(&result), nil
+}
+
+// callookRecord converts a callook.info response to a provider-neutral record
+func callookRecord(result *models.CallsignResponse) *models.CallsignRecord {
+	record := &models.CallsignRecord{
+		Callsign:         result.Current.Callsign,
+		Name:             result.Name,
+		LicenseClass:     result.Current.OperClass,
+		Type:             result.Type,
+		AddressLine1:     result.Address.Line1,
+		AddressLine2:     result.Address.Line2,
+		Country:          "United States",
+		Gridsquare:       result.Location.Gridsquare,
+		GrantDate:        result.OtherInfo.GrantDate,
+		ExpiryDate:       result.OtherInfo.ExpiryDate,
+		LastActionDate:   result.OtherInfo.LastActionDate,
+		Frn:              result.OtherInfo.Frn,
+		PreviousCallsign: result.Previous.Callsign,
+		PreviousClass:    result.Previous.OperClass,
+		URL:              result.OtherInfo.UlsUrl,
+		Source:           "callook.info",
+	}
+	setLocation(record, result.Location.Latitude, result.Location.Longitude)
+
+	return record
+}
@@ -0,0 +1,119 @@
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+const (
+	hamDBBaseURL = "https://api.hamdb.org/v1/"
+)
+
+// hamDBResponse represents the HamDB JSON API response
+type hamDBResponse struct {
+	HamDB struct {
+		Callsign struct {
+			Call    string `json:"call"`
+			Class   string `json:"class"`
+			Expires string `json:"expires"`
+			Grid    string `json:"grid"`
+			Lat     string `json:"lat"`
+			Lon     string `json:"lon"`
+			FName   string `json:"fname"`
+			MI      string `json:"mi"`
+			Name    string `json:"name"`
+			Suffix  string `json:"suffix"`
+			Addr1   string `json:"addr1"`
+			Addr2   string `json:"addr2"`
+			State   string `json:"state"`
+			Zip     string `json:"zip"`
+			Country string `json:"country"`
+		} `json:"callsign"`
+		Messages struct {
+			Status string `json:"status"`
+		} `json:"messages"`
+	} `json:"hamdb"`
+}
+
+// HamDB looks up callsigns using the free HamDB.org API, which aggregates
+// the US, Canadian and several other national databases
+type HamDB struct {
+	client  *upstream.Client
+	baseURL string
+}
+
+// NewHamDB creates a HamDB provider. An empty baseURL selects the public
+// service.
+func NewHamDB(client *upstream.Client, baseURL string) *HamDB {
+	if baseURL == "" {
+		baseURL = hamDBBaseURL
+	}
+	return &HamDB{client: client, baseURL: baseURL}
+}
+
+// Name returns the provider name
+func (h *HamDB) Name() string {
+	return "hamdb"
+}
+
+// Lookup looks up a callsign using the HamDB API
+func (h *HamDB) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	apiURL, err := url.JoinPath(h.baseURL, url.PathEscape(callsign), "json", userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making API request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK response status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	var result hamDBResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	}
+
+	c := result.HamDB.Callsign
+	if result.HamDB.Messages.Status == "NOT_FOUND" || c.Call == "" || c.Call == "NOT_FOUND" {
+		return nil, ErrNotFound
+	}
+	if result.HamDB.Messages.Status != "OK" {
+		return nil, fmt.Errorf("HamDB error: %s", result.HamDB.Messages.Status)
+	}
+
+	record := &models.CallsignRecord{
+		Callsign:     strings.ToUpper(c.Call),
+		Name:         joinNonEmpty(" ", c.FName, c.MI, c.Name, c.Suffix),
+		LicenseClass: c.Class,
+		AddressLine1: c.Addr1,
+		AddressLine2: joinNonEmpty(", ", c.Addr2, joinNonEmpty(" ", c.State, c.Zip)),
+		Country:      c.Country,
+		Gridsquare:   c.Grid,
+		ExpiryDate:   c.Expires,
+		Source:       "hamdb.org",
+	}
+	setLocation(record, c.Lat, c.Lon)
+
+	return record, nil
+}
@@ -0,0 +1,198 @@
+package lookup
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+const (
+	hamQTHBaseURL = "https://www.hamqth.com/xml.php"
+)
+
+// hamQTHResponse represents the HamQTH XML API response
+type hamQTHResponse struct {
+	XMLName xml.Name `xml:"HamQTH"`
+	Session struct {
+		SessionID string `xml:"session_id"`
+		Error     string `xml:"error"`
+	} `xml:"session"`
+	Search struct {
+		Callsign   string `xml:"callsign"`
+		Nick       string `xml:"nick"`
+		QTH        string `xml:"qth"`
+		Country    string `xml:"country"`
+		Grid       string `xml:"grid"`
+		AdrName    string `xml:"adr_name"`
+		AdrStreet1 string `xml:"adr_street1"`
+		AdrCity    string `xml:"adr_city"`
+		AdrZip     string `xml:"adr_zip"`
+		AdrCountry string `xml:"adr_country"`
+		Latitude   string `xml:"latitude"`
+		Longitude  string `xml:"longitude"`
+	} `xml:"search"`
+}
+
+// HamQTH looks up callsigns using the free HamQTH.com XML API, which
+// requires a (free) account. The session ID is obtained on first use and
+// renewed when it expires.
+type HamQTH struct {
+	client   *upstream.Client
+	baseURL  string
+	username string
+	password string
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewHamQTH creates a HamQTH provider using the given account credentials.
+// An empty baseURL selects the public service.
+func NewHamQTH(client *upstream.Client, baseURL, username, password string) *HamQTH {
+	if baseURL == "" {
+		baseURL = hamQTHBaseURL
+	}
+	return &HamQTH{
+		client:   client,
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+	}
+}
+
+// Name returns the provider name
+func (h *HamQTH) Name() string {
+	return "hamqth"
+}
+
+// Lookup looks up a callsign, logging in first if there is no session
+func (h *HamQTH) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	id, err := h.session(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := h.lookup(ctx, id, callsign)
+	if errors.Is(err, errSessionExpired) {
+		// Log in again and retry once
+		if id, err = h.session(ctx, true); err != nil {
+			return nil, err
+		}
+		record, err = h.lookup(ctx, id, callsign)
+	}
+
+	return record, err
+}
+
+// session returns the current session ID, logging in when there is none
+// or when renew is set
+func (h *HamQTH) session(ctx context.Context, renew bool) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.sessionID != "" && !renew {
+		return h.sessionID, nil
+	}
+
+	params := url.Values{}
+	params.Set("u", h.username)
+	params.Set("p", h.password)
+
+	result, err := h.fetch(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	if result.Session.SessionID == "" {
+		return "", fmt.Errorf("HamQTH login failed: %s", result.Session.Error)
+	}
+
+	h.sessionID = result.Session.SessionID
+	return h.sessionID, nil
+}
+
+// lookup performs a callsign query with an existing session ID
+func (h *HamQTH) lookup(ctx context.Context, id, callsign string) (*models.CallsignRecord, error) {
+	params := url.Values{}
+	params.Set("id", id)
+	params.Set("callsign", callsign)
+	params.Set("prg", userAgent)
+
+	result, err := h.fetch(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg := result.Session.Error; msg != "" {
+		switch {
+		case strings.Contains(msg, "not found"):
+			return nil, ErrNotFound
+		case strings.Contains(msg, "expired") || strings.Contains(msg, "does not exist"):
+			return nil, errSessionExpired
+		default:
+			return nil, fmt.Errorf("HamQTH error: %s", msg)
+		}
+	}
+	if result.Search.Callsign == "" {
+		return nil, ErrNotFound
+	}
+
+	s := result.Search
+	name := s.AdrName
+	if name == "" {
+		name = s.Nick
+	}
+	record := &models.CallsignRecord{
+		Callsign:     strings.ToUpper(s.Callsign),
+		Name:         name,
+		AddressLine1: s.AdrStreet1,
+		AddressLine2: joinNonEmpty(" ", s.AdrZip, s.AdrCity),
+		Country:      s.Country,
+		Gridsquare:   s.Grid,
+		URL:          fmt.Sprintf("https://www.hamqth.com/%s", url.PathEscape(strings.ToLower(s.Callsign))),
+		Source:       "hamqth.com",
+	}
+	if record.AddressLine2 == "" {
+		record.AddressLine2 = s.QTH
+	}
+	setLocation(record, s.Latitude, s.Longitude)
+
+	return record, nil
+}
+
+// fetch sends a request to the XML API and decodes the response
+func (h *HamQTH) fetch(ctx context.Context, params url.Values) (*hamQTHResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", requestError(err))
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making API request: %v", requestError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK response status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	var result hamQTHResponse
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error parsing XML response: %v", err)
+	}
+
+	return &result, nil
+}
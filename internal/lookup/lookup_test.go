@@ -0,0 +1,59 @@
+package lookup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// pathServer serves the body at the given path and 404 elsewhere. Its URL
+// has no trailing slash, as a stand-in configured in upstream.base_urls may
+// not.
+func pathServer(t *testing.T, bodies map[string]string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestStandInBaseURLs(t *testing.T) {
+	baseURL := pathServer(t, map[string]string{
+		"/W1AW/json":                     `{"status": "VALID", "type": "CLUB", "current": {"callsign": "W1AW"}, "location": {"gridsquare": "FN31pr"}}`,
+		"/W1AW/json/ham-radio-assistant": `{"hamdb": {"callsign": {"call": "w1aw", "grid": "FN31pr"}, "messages": {"status": "OK"}}}`,
+		"/NOCALL/json":                   `{"status": "INVALID"}`,
+	})
+	client := upstream.New(upstream.Options{Retries: -1})
+
+	providers := []CallsignProvider{
+		NewCallook(client, baseURL),
+		NewCallook(client, baseURL+"/"),
+		NewHamDB(client, baseURL),
+		NewHamDB(client, baseURL+"/"),
+	}
+	for _, provider := range providers {
+		record, err := provider.Lookup(context.Background(), "W1AW")
+		if err != nil {
+			t.Errorf("%s: Lookup: %v", provider.Name(), err)
+			continue
+		}
+		if record.Callsign != "W1AW" || record.Gridsquare != "FN31pr" {
+			t.Errorf("%s: Lookup = %s %s, want W1AW FN31pr", provider.Name(), record.Callsign, record.Gridsquare)
+		}
+	}
+
+	if _, err := NewCallook(client, baseURL).Lookup(context.Background(), "NOCALL"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("callook: Lookup(NOCALL) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package lookup provides callsign lookup providers backed by online
+// databases such as callook.info, QRZ.com, HamQTH and HamDB
+package lookup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// ErrNotFound is returned when a provider has no record for a callsign
+var ErrNotFound = errors.New("callsign not found")
+
+// userAgent identifies this application to upstream services that require it
+const userAgent = "ham-radio-assistant"
+
+// CallsignProvider looks up callsign records from a single source
+type CallsignProvider interface {
+	// Name returns a short identifier for the provider (e.g. "callook")
+	Name() string
+	// Lookup returns the record for the callsign or ErrNotFound
+	Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error)
+}
+
+// Chain queries a list of providers in order and returns the first match
+type Chain struct {
+	providers []CallsignProvider
+}
+
+// NewChain creates a provider chain that tries each provider in order
+func NewChain(providers ...CallsignProvider) *Chain {
+	return &Chain{providers: providers}
+}
+
+// Name returns the names of the chained providers
+func (c *Chain) Name() string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Lookup tries each provider in turn. A provider that has no record or that
+// fails falls through to the next one. ErrNotFound is only returned when no
+// provider returned an error other than not found.
+func (c *Chain) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	var errs []error
+	for _, p := range c.providers {
+		record, err := p.Lookup(ctx, callsign)
+		if err == nil {
+			return record, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, ErrNotFound
+}
+
+// setLocation parses string coordinates into the record, leaving HasLocation
+// false when either value is missing or malformed
+func setLocation(record *models.CallsignRecord, lat, lon string) {
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return
+	}
+	record.Latitude = latitude
+	record.Longitude = longitude
+	record.HasLocation = true
+}
+
+// joinNonEmpty joins the non-empty parts with the separator
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+// requestError describes a failed request by the host it was sent to and the
+// underlying error, leaving out the request URL, whose query string carries
+// the account credentials of the QRZ and HamQTH logins
+func requestError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil && u.Host != "" {
+		return fmt.Errorf("%s: %w", u.Host, urlErr.Err)
+	}
+	return urlErr.Err
+}
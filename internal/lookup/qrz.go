@@ -0,0 +1,201 @@
+package lookup
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+const (
+	qrzBaseURL = "https://xmldata.qrz.com/xml/current/"
+)
+
+// errSessionExpired signals that a session key must be renewed
+var errSessionExpired = errors.New("session expired")
+
+// qrzResponse represents the QRZ.com XML data service response
+type qrzResponse struct {
+	XMLName  xml.Name `xml:"QRZDatabase"`
+	Callsign struct {
+		Call    string `xml:"call"`
+		FName   string `xml:"fname"`
+		Name    string `xml:"name"`
+		Addr1   string `xml:"addr1"`
+		Addr2   string `xml:"addr2"`
+		State   string `xml:"state"`
+		Zip     string `xml:"zip"`
+		Country string `xml:"country"`
+		Lat     string `xml:"lat"`
+		Lon     string `xml:"lon"`
+		Grid    string `xml:"grid"`
+		Class   string `xml:"class"`
+		EfDate  string `xml:"efdate"`
+		ExpDate string `xml:"expdate"`
+		PCall   string `xml:"p_call"`
+	} `xml:"Callsign"`
+	Session struct {
+		Key   string `xml:"Key"`
+		Error string `xml:"Error"`
+	} `xml:"Session"`
+}
+
+// QRZ looks up callsigns using the QRZ.com XML data service. A subscription
+// is required for full records; the session key is obtained on first use
+// and renewed when it expires.
+type QRZ struct {
+	client   *upstream.Client
+	baseURL  string
+	username string
+	password string
+
+	mu         sync.Mutex
+	sessionKey string
+}
+
+// NewQRZ creates a QRZ.com provider using the given account credentials.
+// An empty baseURL selects the public service.
+func NewQRZ(client *upstream.Client, baseURL, username, password string) *QRZ {
+	if baseURL == "" {
+		baseURL = qrzBaseURL
+	}
+	return &QRZ{
+		client:   client,
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+	}
+}
+
+// Name returns the provider name
+func (q *QRZ) Name() string {
+	return "qrz"
+}
+
+// Lookup looks up a callsign, logging in first if there is no session
+func (q *QRZ) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	key, err := q.session(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := q.lookup(ctx, key, callsign)
+	if errors.Is(err, errSessionExpired) {
+		// Log in again and retry once
+		if key, err = q.session(ctx, true); err != nil {
+			return nil, err
+		}
+		record, err = q.lookup(ctx, key, callsign)
+	}
+
+	return record, err
+}
+
+// session returns the current session key, logging in when there is none
+// or when renew is set
+func (q *QRZ) session(ctx context.Context, renew bool) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.sessionKey != "" && !renew {
+		return q.sessionKey, nil
+	}
+
+	params := url.Values{}
+	params.Set("username", q.username)
+	params.Set("password", q.password)
+	params.Set("agent", userAgent)
+
+	result, err := q.fetch(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	if result.Session.Key == "" {
+		return "", fmt.Errorf("QRZ login failed: %s", result.Session.Error)
+	}
+
+	q.sessionKey = result.Session.Key
+	return q.sessionKey, nil
+}
+
+// lookup performs a callsign query with an existing session key
+func (q *QRZ) lookup(ctx context.Context, key, callsign string) (*models.CallsignRecord, error) {
+	params := url.Values{}
+	params.Set("s", key)
+	params.Set("callsign", callsign)
+
+	result, err := q.fetch(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Session.Key == "" {
+		return nil, errSessionExpired
+	}
+	if strings.HasPrefix(result.Session.Error, "Not found") {
+		return nil, ErrNotFound
+	}
+	if result.Session.Error != "" {
+		return nil, fmt.Errorf("QRZ error: %s", result.Session.Error)
+	}
+	if result.Callsign.Call == "" {
+		return nil, ErrNotFound
+	}
+
+	c := result.Callsign
+	record := &models.CallsignRecord{
+		Callsign:         strings.ToUpper(c.Call),
+		Name:             joinNonEmpty(" ", c.FName, c.Name),
+		LicenseClass:     c.Class,
+		AddressLine1:     c.Addr1,
+		AddressLine2:     joinNonEmpty(", ", c.Addr2, joinNonEmpty(" ", c.State, c.Zip)),
+		Country:          c.Country,
+		Gridsquare:       c.Grid,
+		GrantDate:        c.EfDate,
+		ExpiryDate:       c.ExpDate,
+		PreviousCallsign: c.PCall,
+		URL:              fmt.Sprintf("https://www.qrz.com/db/%s", url.PathEscape(strings.ToUpper(c.Call))),
+		Source:           "qrz.com",
+	}
+	setLocation(record, c.Lat, c.Lon)
+
+	return record, nil
+}
+
+// fetch sends a request to the XML data service and decodes the response
+func (q *QRZ) fetch(ctx context.Context, params url.Values) (*qrzResponse, error) {
+	// QRZ expects parameters separated by semicolons, but also accepts ampersands
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", requestError(err))
+	}
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making API request: %v", requestError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-OK response status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	var result qrzResponse
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error parsing XML response: %v", err)
+	}
+
+	return &result, nil
+}
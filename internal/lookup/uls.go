@@ -0,0 +1,41 @@
+package lookup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/uls"
+)
+
+// ULS looks up US callsigns in a local copy of the FCC ULS amateur
+// database imported with uls-import, so lookups work without network access
+type ULS struct {
+	store *uls.Store
+}
+
+// NewULS creates a provider backed by an opened ULS store
+func NewULS(store *uls.Store) *ULS {
+	return &ULS{store: store}
+}
+
+// Name returns the provider name
+func (u *ULS) Name() string {
+	return "uls"
+}
+
+// Lookup looks up a callsign in the local ULS store
+func (u *ULS) Lookup(ctx context.Context, callsign string) (*models.CallsignRecord, error) {
+	result, err := u.store.Lookup(callsign)
+	if errors.Is(err, uls.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := callookRecord(result)
+	record.Source = "FCC ULS (offline)"
+
+	return record, nil
+}
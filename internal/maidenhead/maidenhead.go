@@ -0,0 +1,176 @@
+// Package maidenhead converts between Maidenhead grid locators (e.g. FN31pr)
+// and latitude/longitude, at precisions from 2 to 10 characters
+package maidenhead
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalid is returned for malformed grid locators
+var ErrInvalid = errors.New("invalid grid locator")
+
+// MaxPrecision is the longest supported locator length in characters
+const MaxPrecision = 10
+
+// pair describes one character pair of a locator: the number of divisions
+// and whether it is written with letters or digits
+type pair struct {
+	divisions int
+	letters   bool
+}
+
+// pairs lists the field, square, subsquare, extended square and extended
+// subsquare divisions in order
+var pairs = []pair{
+	{18, true},
+	{10, false},
+	{24, true},
+	{10, false},
+	{24, true},
+}
+
+// Square is the area covered by a grid locator
+type Square struct {
+	Locator string  `json:"locator"`
+	South   float64 `json:"south"`
+	West    float64 `json:"west"`
+	North   float64 `json:"north"`
+	East    float64 `json:"east"`
+}
+
+// Center returns the latitude and longitude of the center of the square
+func (s Square) Center() (lat, lon float64) {
+	return (s.South + s.North) / 2, (s.West + s.East) / 2
+}
+
+// Parse decodes a grid locator of 2, 4, 6, 8 or 10 characters. Letters are
+// case-insensitive; the result uses the conventional FN31pr capitalization.
+func Parse(locator string) (Square, error) {
+	locator = strings.TrimSpace(locator)
+	if len(locator) < 2 || len(locator) > MaxPrecision || len(locator)%2 != 0 {
+		return Square{}, fmt.Errorf("%w: %q must have 2, 4, 6, 8 or 10 characters", ErrInvalid, locator)
+	}
+
+	lonSize, latSize := 360.0, 180.0
+	west, south := -180.0, -90.0
+	for i := 0; i < len(locator); i += 2 {
+		p := pairs[i/2]
+		lonIndex, err := decodeChar(locator[i], p)
+		if err != nil {
+			return Square{}, fmt.Errorf("%w: %q: %v", ErrInvalid, locator, err)
+		}
+		latIndex, err := decodeChar(locator[i+1], p)
+		if err != nil {
+			return Square{}, fmt.Errorf("%w: %q: %v", ErrInvalid, locator, err)
+		}
+
+		lonSize /= float64(p.divisions)
+		latSize /= float64(p.divisions)
+		west += float64(lonIndex) * lonSize
+		south += float64(latIndex) * latSize
+	}
+
+	return Square{
+		Locator: normalize(locator),
+		South:   south,
+		West:    west,
+		North:   south + latSize,
+		East:    west + lonSize,
+	}, nil
+}
+
+// Center decodes a grid locator and returns the latitude and longitude of its center
+func Center(locator string) (lat, lon float64, err error) {
+	square, err := Parse(locator)
+	if err != nil {
+		return 0, 0, err
+	}
+	lat, lon = square.Center()
+	return lat, lon, nil
+}
+
+// Encode returns the grid locator of the given precision (2, 4, 6, 8 or 10
+// characters) containing the latitude and longitude
+func Encode(lat, lon float64, precision int) (string, error) {
+	if precision < 2 || precision > MaxPrecision || precision%2 != 0 {
+		return "", fmt.Errorf("%w: precision %d must be 2, 4, 6, 8 or 10", ErrInvalid, precision)
+	}
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return "", fmt.Errorf("latitude %f out of range", lat)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return "", fmt.Errorf("longitude %f out of range", lon)
+	}
+
+	// Shift to positive offsets and keep the poles and antimeridian inside
+	// the last square
+	lonRem := math.Min(lon+180, math.Nextafter(360, 0))
+	latRem := math.Min(lat+90, math.Nextafter(180, 0))
+
+	lonSize, latSize := 360.0, 180.0
+	var locator strings.Builder
+	for i := 0; i < precision/2; i++ {
+		p := pairs[i]
+		lonSize /= float64(p.divisions)
+		latSize /= float64(p.divisions)
+
+		lonIndex := min(int(lonRem/lonSize), p.divisions-1)
+		latIndex := min(int(latRem/latSize), p.divisions-1)
+		lonRem -= float64(lonIndex) * lonSize
+		latRem -= float64(latIndex) * latSize
+
+		locator.WriteByte(encodeChar(lonIndex, p, i))
+		locator.WriteByte(encodeChar(latIndex, p, i))
+	}
+
+	return locator.String(), nil
+}
+
+// Valid reports whether the string is a well-formed grid locator
+func Valid(locator string) bool {
+	_, err := Parse(locator)
+	return err == nil
+}
+
+// decodeChar returns the division index of a locator character
+func decodeChar(c byte, p pair) (int, error) {
+	var index int
+	if p.letters {
+		index = int(c|0x20) - 'a'
+	} else {
+		index = int(c) - '0'
+	}
+	if index < 0 || index >= p.divisions {
+		return 0, fmt.Errorf("unexpected character %q", c)
+	}
+	return index, nil
+}
+
+// encodeChar returns the locator character for a division index. The field
+// is written in upper case and later letter pairs in lower case.
+func encodeChar(index int, p pair, position int) byte {
+	switch {
+	case !p.letters:
+		return byte('0' + index)
+	case position == 0:
+		return byte('A' + index)
+	default:
+		return byte('a' + index)
+	}
+}
+
+// normalize applies the conventional capitalization to a valid locator
+func normalize(locator string) string {
+	b := []byte(locator)
+	for i := range b {
+		if i < 2 {
+			b[i] &^= 0x20
+		} else if b[i] >= 'A' && b[i] <= 'Z' {
+			b[i] |= 0x20
+		}
+	}
+	return string(b)
+}
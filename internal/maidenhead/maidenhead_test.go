@@ -0,0 +1,116 @@
+package maidenhead
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{41.714775, -72.727260, 2, "FN"},
+		{41.714775, -72.727260, 4, "FN31"},
+		{41.714775, -72.727260, 6, "FN31pr"},
+		{41.714775, -72.727260, 10, "FN31pr21rn"},
+		{-33.8688, 151.2093, 6, "QF56od"},
+		{0, 0, 4, "JJ00"},
+		// The poles and antimeridian fall in the last square
+		{90, 180, 6, "RR99xx"},
+		{-90, -180, 6, "AA00aa"},
+	}
+
+	for _, tt := range tests {
+		got, err := Encode(tt.lat, tt.lon, tt.precision)
+		if err != nil {
+			t.Errorf("Encode(%v, %v, %d): %v", tt.lat, tt.lon, tt.precision, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Encode(%v, %v, %d) = %s, want %s", tt.lat, tt.lon, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+	}{
+		{0, 0, 0},
+		{0, 0, 5},
+		{0, 0, 12},
+		{90.1, 0, 6},
+		{0, -180.1, 6},
+		{math.NaN(), 0, 6},
+	}
+
+	for _, tt := range tests {
+		if got, err := Encode(tt.lat, tt.lon, tt.precision); err == nil {
+			t.Errorf("Encode(%v, %v, %d) = %s, want an error", tt.lat, tt.lon, tt.precision, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		locator                  string
+		want                     string
+		south, west, north, east float64
+	}{
+		{"FN", "FN", 40, -80, 50, -60},
+		{"fn31", "FN31", 41, -74, 42, -72},
+		{" fn31PR ", "FN31pr", 41.708333, -72.750000, 41.750000, -72.666667},
+		{"JJ00aa00", "JJ00aa00", 0, 0, 0.004167, 0.008333},
+	}
+
+	for _, tt := range tests {
+		square, err := Parse(tt.locator)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.locator, err)
+			continue
+		}
+		if square.Locator != tt.want {
+			t.Errorf("Parse(%q).Locator = %s, want %s", tt.locator, square.Locator, tt.want)
+		}
+		got := [...]float64{square.South, square.West, square.North, square.East}
+		want := [...]float64{tt.south, tt.west, tt.north, tt.east}
+		for i := range got {
+			if math.Abs(got[i]-want[i]) > 1e-6 {
+				t.Errorf("Parse(%q) = %v, want %v", tt.locator, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, locator := range []string{"", "F", "FN3", "FN31pr2", "FN31pr21ok00", "SN31", "FNA1", "FN31py", "FN31pr2a"} {
+		if _, err := Parse(locator); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", locator, err)
+		}
+		if Valid(locator) {
+			t.Errorf("Valid(%q) = true", locator)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// The center of every square encodes back to the same locator
+	for _, locator := range []string{"AA00", "FN31pr", "RR99xx", "JO62qm23", "PM95ux42ab"} {
+		lat, lon, err := Center(locator)
+		if err != nil {
+			t.Fatalf("Center(%s): %v", locator, err)
+		}
+		got, err := Encode(lat, lon, len(locator))
+		if err != nil {
+			t.Fatalf("Encode(%v, %v): %v", lat, lon, err)
+		}
+		if got != locator {
+			t.Errorf("Encode(Center(%s)) = %s", locator, got)
+		}
+	}
+}
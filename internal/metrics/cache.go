@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"github.com/pleska/ham-radio-assistant/internal/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Descriptions of the cache metrics, labelled by source
+var (
+	cacheHitsDesc = prometheus.NewDesc(namespace+"_cache_hits_total",
+		"Lookups answered from the cache.", []string{"source"}, nil)
+	cacheMissesDesc = prometheus.NewDesc(namespace+"_cache_misses_total",
+		"Lookups not answered from the cache.", []string{"source"}, nil)
+	cacheHitRatioDesc = prometheus.NewDesc(namespace+"_cache_hit_ratio",
+		"Fraction of lookups answered from the cache since the server started.", []string{"source"}, nil)
+	cacheEntriesDesc = prometheus.NewDesc(namespace+"_cache_entries",
+		"Unexpired entries in the cache.", []string{"source"}, nil)
+)
+
+// cacheCollector reports the cache statistics when the metrics are scraped
+type cacheCollector struct {
+	cache *cache.Cache
+}
+
+// Describe sends the descriptions of the cache metrics
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheHitRatioDesc
+	ch <- cacheEntriesDesc
+}
+
+// Collect sends the current statistics of every cache source
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.cache.Stats() {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(stats.Hits), stats.Source)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(stats.Misses), stats.Source)
+		ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue, stats.HitRatio(), stats.Source)
+		ch <- prometheus.MustNewConstMetric(cacheEntriesDesc, prometheus.GaugeValue, float64(stats.Entries), stats.Source)
+	}
+}
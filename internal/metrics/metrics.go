@@ -0,0 +1,124 @@
+// Package metrics collects Prometheus metrics about tool calls, upstream
+// requests and the response cache, and serves them for scraping.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/cache"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name
+const namespace = "ham"
+
+// Outcomes of a tool call
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// Metrics holds the metrics of one server
+type Metrics struct {
+	registry *prometheus.Registry
+
+	toolCalls        *prometheus.CounterVec
+	toolDuration     *prometheus.HistogramVec
+	toolsInFlight    prometheus.Gauge
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+}
+
+// New creates the metrics, including Go runtime and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_calls_total",
+			Help:      "Tool calls by tool and status (ok or error).",
+		}, []string{"tool", "status"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Time taken by tool calls.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}, []string{"tool"}),
+		toolsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tool_calls_in_flight",
+			Help:      "Tool calls currently being handled.",
+		}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_requests_total",
+			Help:      "Upstream request attempts by host and status code, or error when no response was received.",
+		}, []string{"host", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Time taken by upstream request attempts until the response headers arrived.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2.5, 8),
+		}, []string{"host"}),
+	}
+
+	m.registry.MustRegister(
+		m.toolCalls,
+		m.toolDuration,
+		m.toolsInFlight,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterCache adds the statistics of a cache to the metrics
+func (m *Metrics) RegisterCache(c *cache.Cache) {
+	m.registry.MustRegister(&cacheCollector{cache: c})
+}
+
+// Middleware is tool handler middleware that counts and times tool calls
+func (m *Metrics) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		m.toolsInFlight.Inc()
+		defer m.toolsInFlight.Dec()
+
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		status := statusOK
+		if err != nil || (result != nil && result.IsError) {
+			status = statusError
+		}
+		tool := request.Params.Name
+		m.toolCalls.WithLabelValues(tool, status).Inc()
+		m.toolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+
+		return result, err
+	}
+}
+
+// ObserveUpstream records an upstream request attempt
+func (m *Metrics) ObserveUpstream(request upstream.Request) {
+	status := statusError
+	if request.Error == "" {
+		status = strconv.Itoa(request.Status)
+	}
+	m.upstreamRequests.WithLabelValues(request.Host, status).Inc()
+	m.upstreamDuration.WithLabelValues(request.Host).Observe(request.Duration.Seconds())
+}
@@ -35,3 +35,27 @@ type CallsignResponse struct {
 		UlsUrl         string `json:"ulsUrl"`
 	} `json:"otherInfo"`
 }
+
+// CallsignRecord is a provider-neutral view of an amateur radio callsign,
+// populated by whichever lookup provider answered the query
+type CallsignRecord struct {
+	Callsign         string  `json:"callsign"`
+	Name             string  `json:"name"`
+	LicenseClass     string  `json:"licenseClass"`
+	Type             string  `json:"type"`
+	AddressLine1     string  `json:"addressLine1"`
+	AddressLine2     string  `json:"addressLine2"`
+	Country          string  `json:"country"`
+	Gridsquare       string  `json:"gridsquare"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	HasLocation      bool    `json:"hasLocation"`
+	GrantDate        string  `json:"grantDate"`
+	ExpiryDate       string  `json:"expiryDate"`
+	LastActionDate   string  `json:"lastActionDate"`
+	Frn              string  `json:"frn"`
+	PreviousCallsign string  `json:"previousCallsign"`
+	PreviousClass    string  `json:"previousClass"`
+	URL              string  `json:"url"`
+	Source           string  `json:"source"`
+}
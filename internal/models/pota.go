@@ -39,21 +39,21 @@ func (p *ParkReference) IsActive() bool {
 
 // POTASpot represents a spot of a POTA activation
 type POTASpot struct {
-	SpotID       int     `json:"spotId"`
-	Activator    string  `json:"activator"`
-	Frequency    string  `json:"frequency"`
-	Mode         string  `json:"mode"`
-	Reference    string  `json:"reference"`
-	SpotTime     string  `json:"spotTime"`
-	Spotter      string  `json:"spotter"`
-	Comments     string  `json:"comments"`
-	Source       string  `json:"source"`
-	Name         string  `json:"name"`
-	LocationDesc string  `json:"locationDesc"`
-	Grid4        string  `json:"grid4"`
-	Grid6        string  `json:"grid6"`
-	Latitude     float64 `json:"latitude"`
-	Longitude    float64 `json:"longitude"`
-	Count        int     `json:"count"`
-	Expire       int     `json:"expire"`
+	SpotID       int     `json:"spotId" csv:"spotId"`
+	Activator    string  `json:"activator" csv:"activator"`
+	Frequency    string  `json:"frequency" csv:"frequency"`
+	Mode         string  `json:"mode" csv:"mode"`
+	Reference    string  `json:"reference" csv:"reference"`
+	SpotTime     string  `json:"spotTime" csv:"spotTime"`
+	Spotter      string  `json:"spotter" csv:"spotter"`
+	Comments     string  `json:"comments" csv:"comments"`
+	Source       string  `json:"source" csv:"source"`
+	Name         string  `json:"name" csv:"name"`
+	LocationDesc string  `json:"locationDesc" csv:"locationDesc"`
+	Grid4        string  `json:"grid4" csv:"grid4"`
+	Grid6        string  `json:"grid6" csv:"grid6"`
+	Latitude     float64 `json:"latitude" csv:"latitude"`
+	Longitude    float64 `json:"longitude" csv:"longitude"`
+	Count        int     `json:"count" csv:"count"`
+	Expire       int     `json:"expire" csv:"expire"`
 }
@@ -0,0 +1,280 @@
+package pota
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gocarina/gocsv"
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// catalogRow is a line of the all_parks_ext.csv file published by pota.app
+type catalogRow struct {
+	Reference    string  `csv:"reference"`
+	Name         string  `csv:"name"`
+	Active       int     `csv:"active"`
+	EntityID     int     `csv:"entityId"`
+	LocationDesc string  `csv:"locationDesc"`
+	Latitude     float64 `csv:"latitude"`
+	Longitude    float64 `csv:"longitude"`
+	Grid         string  `csv:"grid"`
+}
+
+// Catalog is an offline list of every POTA park, loaded from the parks CSV
+type Catalog struct {
+	parks       []models.ParkReference
+	byReference map[string]int
+}
+
+// LoadCatalog reads the all_parks_ext.csv file from https://pota.app
+func LoadCatalog(path string) (*Catalog, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open POTA parks file: %w", err)
+	}
+	defer file.Close()
+
+	// Skip the byte order mark that spreadsheet exports start with
+	reader := bufio.NewReader(file)
+	if bom, _ := reader.Peek(3); bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		reader.Discard(3)
+	}
+
+	var rows []catalogRow
+	if err := gocsv.Unmarshal(reader, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse POTA parks file %s: %w", path, err)
+	}
+
+	c := &Catalog{
+		parks:       make([]models.ParkReference, 0, len(rows)),
+		byReference: make(map[string]int, len(rows)),
+	}
+	for _, row := range rows {
+		if row.Reference == "" {
+			continue
+		}
+		park := models.ParkReference{
+			Reference:    strings.ToUpper(row.Reference),
+			Name:         row.Name,
+			Active:       row.Active,
+			EntityID:     row.EntityID,
+			LocationDesc: row.LocationDesc,
+			Latitude:     row.Latitude,
+			Longitude:    row.Longitude,
+			Grid6:        row.Grid,
+		}
+		park.Grid4 = row.Grid[:min(4, len(row.Grid))]
+		park.ReferencePrefix, _, _ = strings.Cut(park.Reference, "-")
+
+		c.parks = append(c.parks, park)
+	}
+	if len(c.parks) == 0 {
+		return nil, fmt.Errorf("no parks found in POTA parks file %s", path)
+	}
+
+	// The file is not sorted, and a reference listed twice keeps its last row
+	sort.SliceStable(c.parks, func(i, j int) bool {
+		return referenceLess(c.parks[i].Reference, c.parks[j].Reference)
+	})
+	for i, park := range c.parks {
+		c.byReference[park.Reference] = i
+	}
+
+	return c, nil
+}
+
+// referenceLess reports whether park reference a sorts before b: by prefix,
+// then by number, so that US-9999 comes before US-10000
+func referenceLess(a, b string) bool {
+	aPrefix, aNumber, _ := strings.Cut(a, "-")
+	bPrefix, bNumber, _ := strings.Cut(b, "-")
+	if aPrefix != bPrefix {
+		return aPrefix < bPrefix
+	}
+	if len(aNumber) != len(bNumber) {
+		return len(aNumber) < len(bNumber)
+	}
+	return aNumber < bNumber
+}
+
+// Parks returns every park in the catalog, in reference order. The slice
+// is shared and must not be modified.
+func (c *Catalog) Parks() []models.ParkReference {
+	return c.parks
+}
+
+// Park returns the park with the given reference (e.g. US-0001). A nil
+// catalog has no parks.
+func (c *Catalog) Park(reference string) (*models.ParkReference, bool) {
+	if c == nil {
+		return nil, false
+	}
+	i, ok := c.byReference[strings.ToUpper(strings.TrimSpace(reference))]
+	if !ok {
+		return nil, false
+	}
+	park := c.parks[i]
+	return &park, true
+}
+
+// Query selects parks in a catalog search. Empty fields match every park.
+type Query struct {
+	// Name is matched loosely against park names, allowing for partial
+	// words and small misspellings
+	Name string
+	// Location is a location code (e.g. US-CO) or a reference prefix
+	// (e.g. US) the park must be in
+	Location string
+	// Type is a kind of park (e.g. State Park, National Forest), matched
+	// against the words of the park name
+	Type string
+	// Active, when set, selects only active (true) or inactive (false) parks
+	Active *bool
+}
+
+// Search returns the parks matching the query, the closest name matches
+// first and otherwise in reference order
+func (c *Catalog) Search(q Query) []models.ParkReference {
+	nameWords := words(q.Name)
+	typeWords := words(q.Type)
+	location := strings.TrimSpace(q.Location)
+
+	type scored struct {
+		index int
+		score int
+	}
+	var matches []scored
+	for i := range c.parks {
+		park := &c.parks[i]
+		if q.Active != nil && park.IsActive() != *q.Active {
+			continue
+		}
+		if location != "" && !InLocation(park.Reference, park.LocationDesc, location) {
+			continue
+		}
+		parkWords := words(park.Name)
+		if len(typeWords) > 0 && !containsPhrase(parkWords, typeWords) {
+			continue
+		}
+		score := 0
+		if len(nameWords) > 0 {
+			if score = nameScore(parkWords, nameWords); score == 0 {
+				continue
+			}
+		}
+		matches = append(matches, scored{index: i, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	parks := make([]models.ParkReference, len(matches))
+	for i, match := range matches {
+		parks[i] = c.parks[match.index]
+	}
+	return parks
+}
+
+// InLocation reports whether a park, given by its reference and location
+// codes, is in the location, given as a location code (e.g. US-CO) or a
+// reference prefix (e.g. US). Parks spanning several locations list them
+// all, separated by commas.
+func InLocation(reference, locationDesc, location string) bool {
+	location = strings.ToUpper(strings.TrimSpace(location))
+	if !strings.Contains(location, "-") {
+		prefix, _, _ := strings.Cut(strings.ToUpper(reference), "-")
+		return prefix == location
+	}
+	for _, code := range strings.Split(locationDesc, ",") {
+		if strings.EqualFold(strings.TrimSpace(code), location) {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lower case words, ignoring punctuation
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsPhrase reports whether the words contain the phrase as
+// consecutive words
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, word := range phrase {
+			if words[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// nameScore rates how well a park name matches the words of a query, or
+// returns zero when some query word has no match. Each query word scores
+// most for an exact word, less for the start of a word and least for a
+// word within one or two edits (longer words allow more).
+func nameScore(name, query []string) int {
+	score := 0
+	for _, q := range query {
+		best := 0
+		for _, word := range name {
+			switch {
+			case word == q:
+				best = max(best, 4)
+			case strings.HasPrefix(word, q):
+				best = max(best, 3)
+			case len(q) >= 4 && editDistance(word, q) <= min(2, len(q)/4):
+				best = max(best, 2)
+			case len(q) >= 4 && strings.Contains(word, q):
+				best = max(best, 1)
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+
+	// Prefer names made up of the query words alone
+	if len(name) == len(query) {
+		score++
+	}
+	return score
+}
+
+// editDistance returns the Levenshtein distance between two words
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
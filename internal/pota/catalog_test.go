@@ -0,0 +1,52 @@
+package pota
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+func references(parks []models.ParkReference) []string {
+	var refs []string
+	for _, park := range parks {
+		refs = append(refs, park.Reference)
+	}
+	return refs
+}
+
+func TestCatalogReferenceOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all_parks_ext.csv")
+	data := `"reference","name","active","entityId","locationDesc","latitude","longitude","grid"
+"US-10000","Pine Lake State Forest","1","291","US-CO","39.5","-105.5","DM79"
+"us-0002","Pine Mountain State Park","1","291","US-GA","32.8","-84.9","EM72"
+"CA-0001","Pine Ridge Provincial Park","1","1","CA-ON","44.0","-79.0","FN04"
+"US-9999","Pine Grove State Park","1","291","US-PA","40.0","-77.0","FN10"
+"US-0001","Acadia National Park","1","291","US-ME","44.31","-68.2034","FN54vh"
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"CA-0001", "US-0001", "US-0002", "US-9999", "US-10000"}
+	if got := references(catalog.Parks()); !slices.Equal(got, want) {
+		t.Errorf("Parks = %v, want %v", got, want)
+	}
+	for _, reference := range want {
+		if park, ok := catalog.Park(reference); !ok || park.Reference != reference {
+			t.Errorf("Park(%s) = %v, %v", reference, park, ok)
+		}
+	}
+
+	// Equally good matches are in reference order
+	want = []string{"CA-0001", "US-0002", "US-9999", "US-10000"}
+	if got := references(catalog.Search(Query{Name: "pine"})); !slices.Equal(got, want) {
+		t.Errorf("Search(pine) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+// Package pota fetches park and spot data from the Parks on the Air API
+package pota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/cache"
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+// DefaultBaseURL is the public POTA API
+const DefaultBaseURL = "https://api.pota.app/"
+
+// Cache sources holding park details and the current spots
+const (
+	ParkCacheSource  = "park"
+	SpotsCacheSource = "spots"
+)
+
+// spotsCacheKey is the cache key of the current spots, which are fetched as a whole
+const spotsCacheKey = "activator"
+
+// ErrNotFound is returned when the API has no record of a park
+var ErrNotFound = errors.New("not found")
+
+// Client queries the POTA API
+type Client struct {
+	client  *upstream.Client
+	baseURL string
+	cache   *cache.Cache
+	maxAge  time.Duration
+}
+
+// NewClient creates a POTA API client. An empty baseURL selects the public
+// API. Responses are cached in c, which may be nil.
+func NewClient(client *upstream.Client, baseURL string, c *cache.Cache) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{client: client, baseURL: baseURL, cache: c}
+}
+
+// WithMaxAge returns a copy of the client that fetches responses again when
+// the cached ones are older than maxAge
+func (c *Client) WithMaxAge(maxAge time.Duration) *Client {
+	clone := *c
+	clone.maxAge = maxAge
+	return &clone
+}
+
+// Park fetches the details of a park by reference (e.g. US-2312)
+func (c *Client) Park(ctx context.Context, reference string) (*models.ParkReference, error) {
+	park, err := cache.GetFresh(ctx, c.cache, ParkCacheSource, strings.ToUpper(reference), c.maxAge, func(ctx context.Context) (*models.ParkReference, error) {
+		var park models.ParkReference
+		if err := c.get(ctx, "park/"+url.PathEscape(reference), &park); err != nil {
+			return nil, err
+		}
+		return &park, nil
+	})
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("park with reference %s %w", reference, ErrNotFound)
+	}
+	return park, err
+}
+
+// Spots fetches the current activator spots
+func (c *Client) Spots(ctx context.Context) ([]models.POTASpot, error) {
+	return cache.GetFresh(ctx, c.cache, SpotsCacheSource, spotsCacheKey, c.maxAge, func(ctx context.Context) ([]models.POTASpot, error) {
+		var spots []models.POTASpot
+		if err := c.get(ctx, "spot/activator", &spots); err != nil {
+			return nil, err
+		}
+		return spots, nil
+	})
+}
+
+// get fetches a path relative to the base URL, which may or may not end in
+// a slash, and decodes the JSON response
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	apiURL, err := url.JoinPath(c.baseURL, path)
+	if err != nil {
+		return fmt.Errorf("invalid POTA API URL: %v", err)
+	}
+	resp, err := c.client.Get(ctx, apiURL)
+	if err != nil {
+		return fmt.Errorf("error connecting to POTA API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received non-OK response status: %s", resp.Status)
+	}
+
+	// Read and parse the JSON response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %v", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parsing JSON data: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package pota
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pleska/ham-radio-assistant/internal/upstream"
+)
+
+func TestClientStandInBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/park/US-0001":
+			w.Write([]byte(`{"reference": "US-0001", "name": "Acadia National Park", "active": 1}`))
+		case "/spot/activator":
+			w.Write([]byte(`[{"spotId": 1, "activator": "W1AW", "reference": "US-0001"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := upstream.New(upstream.Options{Retries: -1})
+
+	// The server URL has no trailing slash
+	for _, baseURL := range []string{server.URL, server.URL + "/"} {
+		potaAPI := NewClient(client, baseURL, nil)
+
+		park, err := potaAPI.Park(context.Background(), "US-0001")
+		if err != nil {
+			t.Errorf("%s: Park: %v", baseURL, err)
+		} else if park.Name != "Acadia National Park" {
+			t.Errorf("%s: Park name = %q", baseURL, park.Name)
+		}
+
+		spots, err := potaAPI.Spots(context.Background())
+		if err != nil {
+			t.Errorf("%s: Spots: %v", baseURL, err)
+		} else if len(spots) != 1 || spots[0].Activator != "W1AW" {
+			t.Errorf("%s: Spots = %+v", baseURL, spots)
+		}
+
+		if _, err := potaAPI.Park(context.Background(), "US-9999"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: Park(US-9999) error = %v, want ErrNotFound", baseURL, err)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package pota
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// spotTimeLayout is the layout of spot times in the API, which are in UTC
+const spotTimeLayout = "2006-01-02T15:04:05"
+
+// maxHistoryLine bounds the length of one spot in the history file
+const maxHistoryLine = 64 * 1024
+
+// historySpot is a spot in the history with its parsed time
+type historySpot struct {
+	spot models.POTASpot
+	time time.Time
+}
+
+// History is a store of past spots, kept in a file across restarts. Spots
+// are told apart by their spot ID and are dropped once they are older than
+// the retention period or the store holds more than its maximum.
+//
+// The file holds one spot per line as JSON. New spots are appended to it,
+// and it is rewritten without the dropped spots once they make up a third
+// of its lines, and on Close.
+type History struct {
+	path      string
+	retention time.Duration
+	maxSpots  int
+
+	// writeMu serializes writes to the file
+	writeMu sync.Mutex
+
+	mu    sync.Mutex
+	spots []historySpot // oldest first
+	ids   map[int]bool
+	// pending are the spots added since the last write
+	pending []models.POTASpot
+	// stale is the number of lines in the file, once pending is written,
+	// whose spots have been dropped
+	stale int
+	// damaged is set when the file has a line that could not be read, which
+	// must be rewritten away before anything is appended after it
+	damaged bool
+}
+
+// OpenHistory opens the spot history kept in path, which is created on the
+// first write when it does not exist. A retention or maxSpots of zero keeps
+// spots regardless of their age or number.
+func OpenHistory(path string, retention time.Duration, maxSpots int) (*History, error) {
+	h := &History{path: path, retention: retention, maxSpots: maxSpots, ids: make(map[int]bool)}
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Add stores the spots that are not in the history yet, drops those past
+// the retention limits and returns the number added. The added spots are
+// written to the file by the next Flush.
+func (h *History) Add(spots []models.POTASpot) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	added := h.insert(spots)
+	h.pending = append(h.pending, added...)
+	h.prune(time.Now())
+
+	return len(added)
+}
+
+// insert adds the spots that are not in the history yet and returns them.
+// The lock must be held.
+func (h *History) insert(spots []models.POTASpot) []models.POTASpot {
+	var added []models.POTASpot
+	for _, spot := range spots {
+		if h.ids[spot.SpotID] {
+			continue
+		}
+		t, err := time.Parse(spotTimeLayout, spot.SpotTime)
+		if err != nil {
+			continue
+		}
+		h.ids[spot.SpotID] = true
+		h.spots = append(h.spots, historySpot{spot: spot, time: t})
+		added = append(added, spot)
+	}
+
+	if len(added) > 0 {
+		// Spots usually arrive in order, so this is cheap
+		sort.SliceStable(h.spots, func(i, j int) bool { return h.spots[i].time.Before(h.spots[j].time) })
+	}
+	return added
+}
+
+// Spots returns the spots spotted within [since, until), newest first. A
+// zero since or until leaves that end of the window open.
+func (h *History) Spots(since, until time.Time) []models.POTASpot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var spots []models.POTASpot
+	for i := len(h.spots) - 1; i >= 0; i-- {
+		s := h.spots[i]
+		if !since.IsZero() && s.time.Before(since) {
+			break
+		}
+		if until.IsZero() || s.time.Before(until) {
+			spots = append(spots, s.spot)
+		}
+	}
+	return spots
+}
+
+// Len returns the number of spots in the history
+func (h *History) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.spots)
+}
+
+// Oldest returns the time of the oldest spot in the history, or the zero
+// time when it is empty
+func (h *History) Oldest() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.spots) == 0 {
+		return time.Time{}
+	}
+	return h.spots[0].time
+}
+
+// prune drops the spots past the retention period and the oldest spots
+// beyond the maximum. The lock must be held.
+func (h *History) prune(now time.Time) {
+	drop := 0
+	if h.retention > 0 {
+		cutoff := now.Add(-h.retention)
+		for drop < len(h.spots) && h.spots[drop].time.Before(cutoff) {
+			drop++
+		}
+	}
+	if h.maxSpots > 0 && len(h.spots)-drop > h.maxSpots {
+		drop = len(h.spots) - h.maxSpots
+	}
+	if drop == 0 {
+		return
+	}
+
+	for _, s := range h.spots[:drop] {
+		delete(h.ids, s.spot.SpotID)
+	}
+	h.spots = append([]historySpot(nil), h.spots[drop:]...)
+	h.stale += drop
+}
+
+// Flush appends the spots added since the last write to the file, or
+// rewrites the file when a third or more of its lines are dropped spots
+func (h *History) Flush() error {
+	return h.write(false)
+}
+
+// Close writes the history to its file one last time, leaving out the
+// dropped spots
+func (h *History) Close() error {
+	return h.write(true)
+}
+
+// write appends the pending spots to the file, or rewrites it when compact
+// is set or too many of its lines are dropped spots
+func (h *History) write(compact bool) error {
+	if h == nil {
+		return nil
+	}
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	h.mu.Lock()
+	lines := len(h.spots) + h.stale
+	if h.stale > 0 && (compact || h.damaged || h.stale*3 >= lines) {
+		spots := make([]models.POTASpot, len(h.spots))
+		for i, s := range h.spots {
+			spots[i] = s.spot
+		}
+		pending, stale, damaged := h.pending, h.stale, h.damaged
+		h.pending, h.stale, h.damaged = nil, 0, false
+		h.mu.Unlock()
+
+		if err := h.rewrite(spots); err != nil {
+			h.restore(pending, stale, damaged)
+			return err
+		}
+		return nil
+	}
+
+	pending := h.pending
+	h.pending = nil
+	h.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := h.append(pending); err != nil {
+		h.restore(pending, 0, false)
+		return err
+	}
+	return nil
+}
+
+// restore puts back the state of the file taken by a failed write so that
+// the next write tries again
+func (h *History) restore(pending []models.POTASpot, stale int, damaged bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending = append(pending, h.pending...)
+	h.stale += stale
+	h.damaged = h.damaged || damaged
+}
+
+// append adds spots to the end of the file
+func (h *History) append(spots []models.POTASpot) error {
+	data, err := encodeSpots(spots)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+	return nil
+}
+
+// rewrite replaces the file with the given spots
+func (h *History) rewrite(spots []models.POTASpot) error {
+	data, err := encodeSpots(spots)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a crash cannot leave a
+	// truncated history behind
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), h.path); err != nil {
+		return fmt.Errorf("error writing spot history: %v", err)
+	}
+
+	return nil
+}
+
+// encodeSpots encodes spots as JSON lines
+func encodeSpots(spots []models.POTASpot) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, spot := range spots {
+		if err := encoder.Encode(spot); err != nil {
+			return nil, fmt.Errorf("error encoding spot history: %v", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// load reads the history file, dropping spots past the retention limits.
+// Lines that cannot be read, such as the last line of an interrupted
+// write, are skipped. A missing file is not an error.
+func (h *History) load() error {
+	file, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading spot history: %v", err)
+	}
+	defer file.Close()
+
+	lines := 0
+	var spots []models.POTASpot
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 4096), maxHistoryLine)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		lines++
+		var spot models.POTASpot
+		if err := json.Unmarshal(scanner.Bytes(), &spot); err != nil {
+			h.damaged = true
+			continue
+		}
+		spots = append(spots, spot)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading spot history %s: %v", h.path, err)
+	}
+
+	h.insert(spots)
+	h.prune(time.Now())
+	// Every line that did not become a spot is rewritten away in time
+	h.stale = lines - len(h.spots)
+	return nil
+}
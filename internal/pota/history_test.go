@@ -0,0 +1,194 @@
+package pota
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// testSpot returns a spot spotted age ago
+func testSpot(id int, age time.Duration) models.POTASpot {
+	return models.POTASpot{
+		SpotID:    id,
+		Activator: "W1AW",
+		Reference: "US-0001",
+		SpotTime:  time.Now().Add(-age).UTC().Format(spotTimeLayout),
+	}
+}
+
+func spotIDs(spots []models.POTASpot) []int {
+	var ids []int
+	for _, spot := range spots {
+		ids = append(ids, spot.SpotID)
+	}
+	return ids
+}
+
+// fileLines returns the number of lines in the history file and its info
+func fileLines(t *testing.T, path string) (int, os.FileInfo) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n")), info
+}
+
+func TestHistoryAdd(t *testing.T) {
+	h, err := OpenHistory(filepath.Join(t.TempDir(), "spots.jsonl"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := h.Add([]models.POTASpot{testSpot(1, 3*time.Minute), testSpot(2, 2*time.Minute), testSpot(2, 2*time.Minute)}); n != 2 {
+		t.Errorf("Add = %d, want 2", n)
+	}
+	if n := h.Add([]models.POTASpot{testSpot(2, 2*time.Minute), testSpot(3, time.Minute)}); n != 1 {
+		t.Errorf("Add = %d, want 1", n)
+	}
+
+	if got := spotIDs(h.Spots(time.Time{}, time.Time{})); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Errorf("Spots = %v, want [3 2 1]", got)
+	}
+	now := time.Now()
+	if got := spotIDs(h.Spots(now.Add(-150*time.Second), now.Add(-90*time.Second))); !slices.Equal(got, []int{2}) {
+		t.Errorf("Spots(window) = %v, want [2]", got)
+	}
+}
+
+func TestHistoryPrune(t *testing.T) {
+	h, err := OpenHistory(filepath.Join(t.TempDir(), "spots.jsonl"), time.Hour, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Add([]models.POTASpot{
+		testSpot(1, 2*time.Hour),
+		testSpot(2, 50*time.Minute),
+		testSpot(3, 40*time.Minute),
+		testSpot(4, 30*time.Minute),
+		testSpot(5, 20*time.Minute),
+	})
+	if got := spotIDs(h.Spots(time.Time{}, time.Time{})); !slices.Equal(got, []int{5, 4, 3}) {
+		t.Errorf("Spots = %v, want [5 4 3]", got)
+	}
+	if oldest := h.Oldest(); time.Since(oldest) < 39*time.Minute || time.Since(oldest) > 41*time.Minute {
+		t.Errorf("Oldest = %v, want 40 minutes ago", oldest)
+	}
+
+	// A dropped spot is new again when it is spotted again
+	if n := h.Add([]models.POTASpot{testSpot(2, 10*time.Minute)}); n != 1 {
+		t.Errorf("Add(dropped spot) = %d, want 1", n)
+	}
+	if got := spotIDs(h.Spots(time.Time{}, time.Time{})); !slices.Equal(got, []int{2, 5, 4}) {
+		t.Errorf("Spots = %v, want [2 5 4]", got)
+	}
+}
+
+func TestHistoryAppendsAndCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spots.jsonl")
+	h, err := OpenHistory(path, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Add([]models.POTASpot{testSpot(1, 9*time.Minute), testSpot(2, 8*time.Minute), testSpot(3, 7*time.Minute), testSpot(4, 6*time.Minute)})
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines, before := fileLines(t, path)
+	if lines != 4 {
+		t.Errorf("lines = %d, want 4", lines)
+	}
+
+	// One dropped spot in five lines does not call for a rewrite
+	h.Add([]models.POTASpot{testSpot(5, 5*time.Minute)})
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines, after := fileLines(t, path)
+	if lines != 5 || !os.SameFile(before, after) {
+		t.Errorf("lines = %d, same file %v, want 5 lines appended", lines, os.SameFile(before, after))
+	}
+
+	// Two in six are a third, so the file is rewritten without them
+	h.Add([]models.POTASpot{testSpot(6, 4*time.Minute)})
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	lines, rewritten := fileLines(t, path)
+	if lines != 4 || os.SameFile(after, rewritten) {
+		t.Errorf("lines = %d, same file %v, want 4 lines rewritten", lines, os.SameFile(after, rewritten))
+	}
+
+	// Close compacts whatever is stale
+	h.Add([]models.POTASpot{testSpot(7, 3*time.Minute)})
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if lines, _ := fileLines(t, path); lines != 4 {
+		t.Errorf("lines after Close = %d, want 4", lines)
+	}
+
+	reopened, err := OpenHistory(path, 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spotIDs(reopened.Spots(time.Time{}, time.Time{})); !slices.Equal(got, []int{7, 6, 5, 4}) {
+		t.Errorf("reopened Spots = %v, want [7 6 5 4]", got)
+	}
+}
+
+func TestHistorySkipsTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spots.jsonl")
+	h, err := OpenHistory(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Add([]models.POTASpot{testSpot(1, 2*time.Minute), testSpot(2, time.Minute)})
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An interrupted append leaves part of a line behind
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"spotId": 3, "activ`)
+	file.Close()
+
+	h, err = OpenHistory(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := h.Len(); n != 2 {
+		t.Errorf("Len = %d, want 2", n)
+	}
+
+	// The next write rewrites the file rather than appending to the line
+	h.Add([]models.POTASpot{testSpot(4, 0)})
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if lines, _ := fileLines(t, path); lines != 3 {
+		t.Errorf("lines = %d, want 3", lines)
+	}
+
+	h, err = OpenHistory(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spotIDs(h.Spots(time.Time{}, time.Time{})); !slices.Equal(got, []int{4, 2, 1}) || h.damaged || h.stale != 0 {
+		t.Errorf("reopened Spots = %v, damaged %v, stale %d, want [4 2 1] undamaged", got, h.damaged, h.stale)
+	}
+}
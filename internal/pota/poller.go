@@ -0,0 +1,50 @@
+package pota
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultPollInterval is how often the current spots are added to the
+// history unless configured
+const DefaultPollInterval = 2 * time.Minute
+
+// Poll adds the current spots to the history right away and then at every
+// interval until the context is done, writing the history to its file
+// after each poll that added spots. Failed polls are logged and retried at
+// the next interval.
+func Poll(ctx context.Context, client *Client, history *History, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		poll(ctx, client, history)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll adds the current spots to the history once
+func poll(ctx context.Context, client *Client, history *History) {
+	spots, err := client.Spots(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.WarnContext(ctx, "error polling POTA spots", "error", err)
+		}
+		return
+	}
+
+	added := history.Add(spots)
+	slog.DebugContext(ctx, "polled POTA spots", "spots", len(spots), "added", added, "history", history.Len())
+	if added == 0 {
+		return
+	}
+	if err := history.Flush(); err != nil {
+		slog.WarnContext(ctx, "error writing spot history", "path", history.path, "error", err)
+	}
+}
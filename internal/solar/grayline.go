@@ -0,0 +1,55 @@
+package solar
+
+import "time"
+
+// grayLineStep is the resolution of the gray-line search
+const grayLineStep = time.Minute
+
+// InGrayLine reports whether a place is in civil twilight, between civil
+// dawn and sunrise or between sunset and civil dusk. HF signals along the
+// terminator are less absorbed by the D layer, which makes long-haul
+// contacts between two stations that are both in twilight more likely.
+func InGrayLine(t time.Time, lat, lon float64) bool {
+	elevation := Elevation(t, lat, lon)
+	return elevation >= CivilElevation && elevation <= SunriseElevation
+}
+
+// Window is a span of time during which two places are both in the gray line
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// FromRising and ToRising report whether it is dawn (rather than dusk)
+	// at each place during the window
+	FromRising bool `json:"fromRising"`
+	ToRising   bool `json:"toRising"`
+}
+
+// GrayLineOverlap finds the windows within the given span from start during
+// which both places are in the gray line. Windows are found to the minute.
+func GrayLineOverlap(start time.Time, span time.Duration, fromLat, fromLon, toLat, toLon float64) []Window {
+	var windows []Window
+	var open *Window
+
+	end := start.Add(span)
+	for t := start; !t.After(end); t = t.Add(grayLineStep) {
+		both := InGrayLine(t, fromLat, fromLon) && InGrayLine(t, toLat, toLon)
+		switch {
+		case both && open == nil:
+			open = &Window{
+				Start:      t,
+				FromRising: Rising(t, fromLat, fromLon),
+				ToRising:   Rising(t, toLat, toLon),
+			}
+		case !both && open != nil:
+			open.End = t
+			windows = append(windows, *open)
+			open = nil
+		}
+	}
+	if open != nil {
+		open.End = end
+		windows = append(windows, *open)
+	}
+
+	return windows
+}
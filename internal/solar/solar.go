@@ -0,0 +1,164 @@
+// Package solar calculates the position of the sun and the times of
+// sunrise, sunset and civil twilight using the NOAA solar calculator
+// algorithm (after Meeus, Astronomical Algorithms), which is accurate to
+// about a minute for latitudes within the polar circles.
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// Sun elevations in degrees that define the daily events. Sunrise and sunset
+// allow for atmospheric refraction and the radius of the solar disk.
+const (
+	SunriseElevation = -0.833
+	CivilElevation   = -6.0
+)
+
+// Day holds the sun events for one location on one date. Events that do not
+// happen on that date, such as sunset during the midnight sun, are zero.
+type Day struct {
+	Date      time.Time
+	CivilDawn time.Time
+	Sunrise   time.Time
+	SolarNoon time.Time
+	Sunset    time.Time
+	CivilDusk time.Time
+	// AlwaysUp is set when the sun does not set, and AlwaysDown when it
+	// does not rise
+	AlwaysUp   bool
+	AlwaysDown bool
+}
+
+// DayLength returns the time between sunrise and sunset
+func (d *Day) DayLength() time.Duration {
+	switch {
+	case d.AlwaysUp:
+		return 24 * time.Hour
+	case d.AlwaysDown, d.Sunrise.IsZero(), d.Sunset.IsZero():
+		return 0
+	}
+	return d.Sunset.Sub(d.Sunrise)
+}
+
+// Times calculates the sun events around the solar noon that falls on the
+// UTC date of the given time. Longitude is east-positive.
+func Times(date time.Time, lat, lon float64) *Day {
+	date = date.UTC()
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	day := &Day{Date: midnight}
+
+	// Solar noon, refined once with the equation of time at noon
+	noon := midnight.Add(time.Duration(720-4*lon) * time.Minute)
+	for i := 0; i < 2; i++ {
+		_, eqTime := position(noon)
+		noon = midnight.Add(minutes(720 - 4*lon - eqTime))
+	}
+	day.SolarNoon = noon
+
+	var sunUp, sunDown bool
+	day.Sunrise, sunUp, sunDown = event(midnight, noon, lat, lon, SunriseElevation, true)
+	day.Sunset, _, _ = event(midnight, noon, lat, lon, SunriseElevation, false)
+	day.AlwaysUp, day.AlwaysDown = sunUp, sunDown
+	day.CivilDawn, _, _ = event(midnight, noon, lat, lon, CivilElevation, true)
+	day.CivilDusk, _, _ = event(midnight, noon, lat, lon, CivilElevation, false)
+
+	return day
+}
+
+// Elevation returns the geometric elevation of the center of the sun above
+// the horizon in degrees at the given time and place
+func Elevation(t time.Time, lat, lon float64) float64 {
+	decl, eqTime := position(t)
+
+	t = t.UTC()
+	utcMinutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+	trueSolarTime := utcMinutes + eqTime + 4*lon
+	hourAngle := trueSolarTime/4 - 180
+
+	latRad := radians(lat)
+	cosZenith := math.Sin(latRad)*math.Sin(decl) +
+		math.Cos(latRad)*math.Cos(decl)*math.Cos(radians(hourAngle))
+	return 90 - degrees(math.Acos(math.Max(-1, math.Min(1, cosZenith))))
+}
+
+// Rising reports whether the sun is climbing at the given time and place
+func Rising(t time.Time, lat, lon float64) bool {
+	return Elevation(t.Add(time.Minute), lat, lon) > Elevation(t, lat, lon)
+}
+
+// event calculates the time the sun crosses the given elevation in the
+// morning (rising) or evening. alwaysAbove and alwaysBelow report that the
+// sun stays on one side of the elevation all day, in which case the
+// returned time is zero.
+func event(midnight, noon time.Time, lat, lon, elevation float64, rising bool) (t time.Time, alwaysAbove, alwaysBelow bool) {
+	latRad := radians(lat)
+	zenith := radians(90 - elevation)
+
+	t = noon
+	for i := 0; i < 3; i++ {
+		decl, eqTime := position(t)
+		cosH := math.Cos(zenith)/(math.Cos(latRad)*math.Cos(decl)) - math.Tan(latRad)*math.Tan(decl)
+		if cosH > 1 {
+			return time.Time{}, false, true
+		}
+		if cosH < -1 {
+			return time.Time{}, true, false
+		}
+
+		hourAngle := degrees(math.Acos(cosH))
+		if !rising {
+			hourAngle = -hourAngle
+		}
+		t = midnight.Add(minutes(720 - 4*(lon+hourAngle) - eqTime))
+	}
+	return t, false, false
+}
+
+// position returns the declination of the sun in radians and the equation
+// of time in minutes
+func position(t time.Time) (decl, eqTime float64) {
+	julianDay := float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+	c := (julianDay - 2451545) / 36525
+
+	meanLong := math.Mod(280.46646+c*(36000.76983+c*0.0003032), 360)
+	meanAnomaly := 357.52911 + c*(35999.05029-0.0001537*c)
+	eccentricity := 0.016708634 - c*(0.000042037+0.0000001267*c)
+
+	m := radians(meanAnomaly)
+	center := math.Sin(m)*(1.914602-c*(0.004817+0.000014*c)) +
+		math.Sin(2*m)*(0.019993-0.000101*c) +
+		math.Sin(3*m)*0.000289
+	omega := radians(125.04 - 1934.136*c)
+	apparentLong := radians(meanLong + center - 0.00569 - 0.00478*math.Sin(omega))
+
+	meanObliquity := 23 + (26+(21.448-c*(46.815+c*(0.00059-c*0.001813)))/60)/60
+	obliquity := radians(meanObliquity + 0.00256*math.Cos(omega))
+
+	decl = math.Asin(math.Sin(obliquity) * math.Sin(apparentLong))
+
+	y := math.Pow(math.Tan(obliquity/2), 2)
+	l0 := radians(meanLong)
+	eqTime = 4 * degrees(y*math.Sin(2*l0)-
+		2*eccentricity*math.Sin(m)+
+		4*eccentricity*y*math.Sin(m)*math.Cos(2*l0)-
+		0.5*y*y*math.Sin(4*l0)-
+		1.25*eccentricity*eccentricity*math.Sin(2*m))
+
+	return decl, eqTime
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// minutes converts fractional minutes to a duration
+func minutes(m float64) time.Duration {
+	return time.Duration(m * float64(time.Minute))
+}
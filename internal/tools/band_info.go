@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/bandplan"
+)
+
+// lowestBandKHz is the lower edge of 160m. Frequencies given without a unit
+// below it are taken to be in MHz.
+const lowestBandKHz = 1800
+
+// RegisterBandInfoTool registers the band plan lookup tool with the MCP server.
+// The region defaults to the station's ITU region.
+func RegisterBandInfoTool(s Registrar, station *Station) {
+	// Add tool
+	tool := mcp.NewTool("band-info",
+		mcp.WithDescription("Look up a frequency or band in the IARU band plan: the band, the CW, data or phone segment and the modes expected there, and the FT8, FT4, JS8 and WSPR dial frequencies"),
+		mcp.WithString("frequency",
+			mcp.Description("Frequency to classify, in kHz or MHz (e.g., 14074, 14.074, 7.185 MHz); numbers below 1800 without a unit are MHz"),
+		),
+		mcp.WithString("band",
+			mcp.Description("Band to describe (e.g., 20m, 40m, 2m, 70cm), when no frequency is given"),
+		),
+		mcp.WithNumber("region",
+			mcp.Description("IARU region of the band plan: 1 (Europe, Africa, Middle East), 2 (Americas) or 3 (Asia-Pacific). Defaults to your station's region."),
+			mcp.Min(1),
+			mcp.Max(3),
+		),
+		outputOption(false),
+	)
+
+	// Add tool handler
+	s.AddTool(tool, BandInfo(station))
+}
+
+// bandInfoResult is the JSON output of the band info tool. Band and Segment
+// are omitted for a frequency outside the amateur bands.
+type bandInfoResult struct {
+	Region       int                      `json:"region"`
+	FrequencyKHz *float64                 `json:"frequencyKHz,omitempty"`
+	Band         *bandplan.Band           `json:"band,omitempty"`
+	Segment      *bandplan.Segment        `json:"segment,omitempty"`
+	Modes        string                   `json:"modes,omitempty"`
+	Dials        []bandplan.DialFrequency `json:"dials"`
+}
+
+// BandInfo returns a tool handler for looking up frequencies and bands in the band plan
+func BandInfo(station *Station) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		frequency, _ := args["frequency"].(string)
+		bandName, _ := args["band"].(string)
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+		if frequency == "" && bandName == "" {
+			return nil, errors.New("a frequency or a band is required")
+		}
+
+		// The region falls back to the station's, then to the default
+		region := request.GetInt("region", station.region())
+		defaulted := region == 0
+		if defaulted {
+			region = bandplan.DefaultRegion
+		}
+		plan, err := bandplan.ForRegion(region)
+		if err != nil {
+			return nil, err
+		}
+
+		var result strings.Builder
+		if frequency != "" {
+			khz, err := parseFrequency(frequency)
+			if err != nil {
+				return nil, err
+			}
+			c, err := bandplan.Classify(region, khz)
+			if err != nil {
+				return nil, err
+			}
+
+			if output == outputJSON {
+				info := bandInfoResult{Region: region, FrequencyKHz: &khz, Band: c.Band, Segment: c.Segment, Dials: c.Dials}
+				if c.Segment != nil {
+					info.Modes = c.Segment.Modes()
+				}
+				if info.Dials == nil {
+					info.Dials = []bandplan.DialFrequency{}
+				}
+				return jsonResult(info)
+			}
+
+			result.WriteString(fmt.Sprintf("## %s MHz\n\n", formatMHz(khz)))
+			result.WriteString(fmt.Sprintf("**Band Plan:** %s\n", describeRegion(region, defaulted)))
+			if c.Band == nil {
+				result.WriteString(fmt.Sprintf("\n%s MHz is outside the amateur bands of IARU Region %d", formatMHz(khz), region))
+				return mcp.NewToolResultText(result.String()), nil
+			}
+			result.WriteString(fmt.Sprintf("**Band:** %s (%s–%s MHz)\n", c.Band.Name, formatMHz(c.Band.LowKHz), formatMHz(c.Band.HighKHz)))
+			if c.Segment != nil {
+				result.WriteString(fmt.Sprintf("**Segment:** %s, %s–%s MHz\n", c.Segment.Use, formatMHz(c.Segment.LowKHz), formatMHz(c.Segment.HighKHz)))
+				result.WriteString(fmt.Sprintf("**Expected Modes:** %s\n", c.Segment.Modes()))
+			}
+			if len(c.Dials) > 0 {
+				dials := make([]string, len(c.Dials))
+				for i, dial := range c.Dials {
+					dials[i] = fmt.Sprintf("%s (dial %s MHz)", dial.Mode, formatMHz(dial.KHz))
+				}
+				result.WriteString(fmt.Sprintf("**Digital Modes:** %s\n", strings.Join(dials, ", ")))
+			}
+			result.WriteString("\nBand plans are a guide; check your license for the frequencies you may use")
+			return mcp.NewToolResultText(result.String()), nil
+		}
+
+		band, ok := plan.BandByName(bandName)
+		if !ok {
+			return nil, fmt.Errorf("no band %q in the IARU Region %d band plan (e.g. 20m, 40m, 2m, 70cm)", bandName, region)
+		}
+		dials := bandplan.DialsInBand(band.Name)
+
+		if output == outputJSON {
+			if dials == nil {
+				dials = []bandplan.DialFrequency{}
+			}
+			return jsonResult(bandInfoResult{Region: region, Band: band, Dials: dials})
+		}
+
+		result.WriteString(fmt.Sprintf("## %s Band\n\n", band.Name))
+		result.WriteString(fmt.Sprintf("**Band Plan:** %s\n", describeRegion(region, defaulted)))
+		result.WriteString(fmt.Sprintf("**Edges:** %s–%s MHz\n\n", formatMHz(band.LowKHz), formatMHz(band.HighKHz)))
+
+		result.WriteString("### Segments\n\n")
+		result.WriteString("| From (MHz) | To (MHz) | Use | Expected Modes |\n")
+		result.WriteString("|------------|----------|-----|----------------|\n")
+		for _, segment := range band.Segments {
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", formatMHz(segment.LowKHz), formatMHz(segment.HighKHz), segment.Use, segment.Modes()))
+		}
+
+		if len(dials) > 0 {
+			result.WriteString("\n### Digital Mode Dial Frequencies\n\n")
+			result.WriteString("| Mode | Dial (MHz) |\n")
+			result.WriteString("|------|------------|\n")
+			for _, dial := range dials {
+				result.WriteString(fmt.Sprintf("| %s | %s |\n", dial.Mode, formatMHz(dial.KHz)))
+			}
+		}
+
+		result.WriteString("\nBand plans are a guide; check your license for the frequencies you may use")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+}
+
+// describeRegion names the band plan in use, noting when the region is the
+// default rather than the station's
+func describeRegion(region int, defaulted bool) string {
+	if defaulted {
+		return fmt.Sprintf("IARU Region %d (default; set station.itu_region for yours)", region)
+	}
+	return fmt.Sprintf("IARU Region %d", region)
+}
+
+// parseFrequency parses a frequency in kHz or MHz, with an optional unit.
+// Without a unit, numbers below the lowest band are taken to be in MHz.
+func parseFrequency(input string) (float64, error) {
+	value := strings.ToLower(strings.TrimSpace(input))
+	scale := 0.0
+	switch {
+	case strings.HasSuffix(value, "mhz"):
+		value, scale = strings.TrimSuffix(value, "mhz"), 1000
+	case strings.HasSuffix(value, "khz"):
+		value, scale = strings.TrimSuffix(value, "khz"), 1
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid frequency %q (e.g. 14074, 14.074 or 7.185 MHz)", input)
+	}
+	if scale == 0 {
+		scale = 1
+		if number < lowestBandKHz {
+			scale = 1000
+		}
+	}
+	return number * scale, nil
+}
+
+// formatMHz formats a frequency in kHz as MHz to the kHz, or to the 100 Hz
+// where that is needed (e.g. 7.0475)
+func formatMHz(khz float64) string {
+	s := strconv.FormatFloat(khz/1000, 'f', 4, 64)
+	return strings.TrimSuffix(s, "0")
+}
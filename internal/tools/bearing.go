@@ -10,83 +10,160 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+	"github.com/tidwall/geodesic"
 )
 
-func RegisterAntennaBearingTool(s *server.MCPServer) {
-	tool := mcp.NewTool("antenna-bearing",
-		mcp.WithDescription("Calculate antenna bearing"),
-		mcp.WithString("origin-latitude",
-			mcp.Required(),
-			mcp.Description("Origin station latitude in decimal degrees"),
-		),
-		mcp.WithString("origin-longitude",
-			mcp.Required(),
-			mcp.Description("Origin station longtitude in decimal degrees"),
-		),
-		mcp.WithString("destination-latitude",
-			mcp.Required(),
-			mcp.Description("Destination station latitude in decimal degrees"),
-		),
-		mcp.WithString("destination-longitude",
-			mcp.Required(),
-			mcp.Description("Destination station longtitude in decimal degrees"),
-		),
-	)
+// RegisterAntennaBearingTool registers the antenna bearing tool with the MCP server.
+// The origin defaults to the station, which may be nil.
+func RegisterAntennaBearingTool(s Registrar, station *Station) {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Calculate antenna bearing between two points given as coordinates or grid squares. The origin defaults to your station."),
+	}
+	options = append(options, endpointOptions("origin", "Origin station")...)
+	options = append(options, endpointOptions("destination", "Destination station")...)
+	options = append(options, modelOption(), outputOption(false))
+	tool := mcp.NewTool("antenna-bearing", options...)
 
 	// Add tool handler
-	s.AddTool(tool, AntennaBearing)
+	s.AddTool(tool, AntennaBearing(station))
 }
 
-func AntennaBearing(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Extract coordinates from request
-	originLatStr, ok := request.Params.Arguments["origin-latitude"].(string)
-	if !ok {
-		return nil, errors.New("origin-latitude must be a string")
+// AntennaBearing returns a tool handler for calculating the bearing between two points
+func AntennaBearing(station *Station) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Extract endpoints from request
+		origin, err := originEndpoint(ctx, request, station)
+		if err != nil {
+			return nil, err
+		}
+		dest, err := parseEndpoint(request, "destination")
+		if err != nil {
+			return nil, err
+		}
+
+		model, err := parseModel(request)
+		if err != nil {
+			return nil, err
+		}
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Calculate short and long path
+		path := calculatePath(origin.Latitude, origin.Longitude, dest.Latitude, dest.Longitude, model)
+
+		if output == outputJSON {
+			return jsonResult(bearingResult{Origin: origin, Destination: dest, Path: path})
+		}
+
+		// Prepare result
+		result := fmt.Sprintf("## Antenna Bearing Results\n\n"+
+			"**Origin:** %s\n"+
+			"**Destination:** %s\n\n"+
+			"**Distance:** %s\n\n"+
+			"**Bearing:** %.2f degrees from North\n\n",
+			origin, dest, station.formatDistance(path.ShortPathKm), path.ShortPathBearing)
+		result += formatLongPath(path, station)
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	originLonStr, ok := request.Params.Arguments["origin-longitude"].(string)
-	if !ok {
-		return nil, errors.New("origin-longitude must be a string")
+// bearingResult is the JSON output of the antenna bearing tool
+type bearingResult struct {
+	Origin      *endpoint `json:"origin"`
+	Destination *endpoint `json:"destination"`
+	Path        pathInfo  `json:"path"`
+}
+
+// endpoint is one end of a path
+type endpoint struct {
+	// Label names the endpoint in results and is empty for plain coordinates
+	Label     string  `json:"label,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Grid is the grid square the endpoint was given as, or the 6-character
+	// grid square containing the coordinates
+	Grid string `json:"grid"`
+}
+
+// String formats the endpoint as coordinates with its grid square
+func (e *endpoint) String() string {
+	if e.Label != "" {
+		return fmt.Sprintf("%s: %.4f, %.4f (%s)", e.Label, e.Latitude, e.Longitude, e.Grid)
 	}
+	return fmt.Sprintf("%.4f, %.4f (%s)", e.Latitude, e.Longitude, e.Grid)
+}
 
-	destLatStr, ok := request.Params.Arguments["destination-latitude"].(string)
-	if !ok {
-		return nil, errors.New("destination-latitude must be a string")
+// endpointOptions returns the tool parameters describing one end of a path,
+// which may be given either as decimal coordinates or as a grid square
+func endpointOptions(name, label string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString(name+"-latitude",
+			mcp.Description(label+" latitude in decimal degrees (required unless "+name+"-grid is given)"),
+		),
+		mcp.WithString(name+"-longitude",
+			mcp.Description(label+" longitude in decimal degrees (required unless "+name+"-grid is given)"),
+		),
+		mcp.WithString(name+"-grid",
+			mcp.Description(label+" Maidenhead grid square (e.g. FN31pr), used in place of coordinates"),
+		),
+	}
+}
+
+// parseEndpoint reads one end of a path from the request. A grid square
+// takes precedence over coordinates and resolves to the center of the square.
+func parseEndpoint(request mcp.CallToolRequest, name string) (*endpoint, error) {
+	args := request.GetArguments()
+
+	if grid, _ := args[name+"-grid"].(string); grid != "" {
+		square, err := maidenhead.Parse(grid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s grid: %v", name, err)
+		}
+		lat, lon := square.Center()
+		return &endpoint{Latitude: lat, Longitude: lon, Grid: square.Locator}, nil
 	}
 
-	destLonStr, ok := request.Params.Arguments["destination-longitude"].(string)
+	latStr, ok := args[name+"-latitude"].(string)
 	if !ok {
-		return nil, errors.New("destination-longitude must be a string")
+		return nil, fmt.Errorf("%s-latitude must be a string or %s-grid must be given", name, name)
+	}
+	lonStr, ok := args[name+"-longitude"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s-longitude must be a string or %s-grid must be given", name, name)
 	}
 
 	// Convert coordinates to float64
-	originLat, err := strconv.ParseFloat(originLatStr, 64)
+	lat, err := strconv.ParseFloat(latStr, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid origin latitude: %v", err)
+		return nil, fmt.Errorf("invalid %s latitude: %v", name, err)
 	}
-	originLon, err := strconv.ParseFloat(originLonStr, 64)
+	lon, err := strconv.ParseFloat(lonStr, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid origin longitude: %v", err)
+		return nil, fmt.Errorf("invalid %s longitude: %v", name, err)
 	}
-	destLat, err := strconv.ParseFloat(destLatStr, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid destination latitude: %v", err)
-	}
-	destLon, err := strconv.ParseFloat(destLonStr, 64)
+
+	grid, err := maidenhead.Encode(lat, lon, 6)
 	if err != nil {
-		return nil, fmt.Errorf("invalid destination longitude: %v", err)
+		return nil, fmt.Errorf("invalid %s coordinates: %v", name, err)
 	}
 
-	// Calculate distance and bearing
-	distanceKm, distanceMiles, bearing := calculateDistanceAndBearing(originLat, originLon, destLat, destLon)
-
-	// Prepare result
-	result := fmt.Sprintf("## Antenna Bearing Results\n\n"+
-		"**Distance:** %.2f miles (%.2f km)\n\n"+
-		"**Bearing:** %.2f degrees from North",
-		distanceMiles, distanceKm, bearing)
+	return &endpoint{Latitude: lat, Longitude: lon, Grid: grid}, nil
+}
 
-	return mcp.NewToolResultText(result), nil
+// originEndpoint reads the origin of a path from the request, defaulting to
+// your station when no origin parameter is given
+func originEndpoint(ctx context.Context, request mcp.CallToolRequest, station *Station) (*endpoint, error) {
+	if !hasEndpoint(request, "origin") {
+		if !station.Configured() {
+			return nil, errors.New("origin must be given when no station is configured")
+		}
+		return station.endpoint(ctx)
+	}
+	return parseEndpoint(request, "origin")
 }
 
 // toRadians converts degrees to radians
@@ -139,3 +216,130 @@ func calculateDistanceAndBearing(lat1, lon1, lat2, lon2 float64) (distanceKm, di
 
 	return distanceKm, distanceMiles, bearing
 }
+
+// Distance models accepted by the bearing tools
+const (
+	modelSpherical = "spherical"
+	modelEllipsoid = "ellipsoid"
+)
+
+// earthCircumferenceKm is the great-circle circumference of the spherical model
+const earthCircumferenceKm = 2 * math.Pi * 6371.0
+
+// kmToMiles converts kilometers to statute miles
+const kmToMiles = 0.621371
+
+// pathInfo holds the short and long path between two points
+type pathInfo struct {
+	ShortPathKm      float64 `json:"shortPathKm"`
+	ShortPathBearing float64 `json:"shortPathBearing"`
+	LongPathKm       float64 `json:"longPathKm"`
+	LongPathBearing  float64 `json:"longPathBearing"`
+	Model            string  `json:"model"`
+}
+
+// modelOption returns the tool parameter selecting the distance model
+func modelOption() mcp.ToolOption {
+	return mcp.WithString("model",
+		mcp.Description("Distance model: spherical (default, haversine) or ellipsoid (WGS-84 geodesic, accurate to millimeters even for near-antipodal paths)"),
+		mcp.Enum(modelSpherical, modelEllipsoid),
+	)
+}
+
+// parseModel reads the distance model from the request
+func parseModel(request mcp.CallToolRequest) (string, error) {
+	model, _ := request.GetArguments()["model"].(string)
+	switch model {
+	case "", modelSpherical:
+		return modelSpherical, nil
+	case modelEllipsoid:
+		return modelEllipsoid, nil
+	default:
+		return "", fmt.Errorf("unknown model %q (expected %s or %s)", model, modelSpherical, modelEllipsoid)
+	}
+}
+
+// calculatePath calculates the short and long path between two points using
+// the given model. The long path leaves in the opposite direction and runs
+// the rest of the way around the Earth.
+func calculatePath(lat1, lon1, lat2, lon2 float64, model string) pathInfo {
+	if model == modelEllipsoid {
+		return calculateGeodesicPath(lat1, lon1, lat2, lon2)
+	}
+
+	distanceKm, _, bearing := calculateDistanceAndBearing(lat1, lon1, lat2, lon2)
+	return pathInfo{
+		ShortPathKm:      distanceKm,
+		ShortPathBearing: bearing,
+		LongPathKm:       earthCircumferenceKm - distanceKm,
+		LongPathBearing:  math.Mod(bearing+180, 360),
+		Model:            modelSpherical,
+	}
+}
+
+// calculateGeodesicPath calculates the short and long path on the WGS-84
+// ellipsoid using Karney's geodesic algorithm. Geodesics on an ellipsoid do
+// not close, so the long path is taken through the antipodes of both ends:
+// origin to antipode(destination), antipode(destination) to antipode(origin)
+// (the same length as the short path) and antipode(origin) to destination
+// (the same length as the first leg).
+func calculateGeodesicPath(lat1, lon1, lat2, lon2 float64) pathInfo {
+	var shortM, shortAzi, longLegM, longAzi float64
+	geodesic.WGS84.Inverse(lat1, lon1, lat2, lon2, &shortM, &shortAzi, nil)
+
+	antiLat, antiLon := antipode(lat2, lon2)
+	geodesic.WGS84.Inverse(lat1, lon1, antiLat, antiLon, &longLegM, &longAzi, nil)
+
+	return pathInfo{
+		ShortPathKm:      shortM / 1000,
+		ShortPathBearing: normalizeBearing(shortAzi),
+		LongPathKm:       (2*longLegM + shortM) / 1000,
+		LongPathBearing:  normalizeBearing(longAzi),
+		Model:            modelEllipsoid,
+	}
+}
+
+// antipode returns the point on the opposite side of the Earth
+func antipode(lat, lon float64) (float64, float64) {
+	if lon > 0 {
+		return -lat, lon - 180
+	}
+	return -lat, lon + 180
+}
+
+// normalizeBearing converts a bearing to the 0-360 range
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, 360)
+	if bearing < 0 {
+		bearing += 360
+	}
+	return bearing
+}
+
+// formatLongPath formats the long path line and the model note
+func formatLongPath(path pathInfo, station *Station) string {
+	result := fmt.Sprintf("**Long Path:** %s at %.1f degrees from North\n\n",
+		station.formatDistance(path.LongPathKm), path.LongPathBearing)
+	if path.Model == modelEllipsoid {
+		return result + "Calculated along the WGS-84 ellipsoid geodesic"
+	}
+	return result + "Calculated on a spherical Earth (6371 km radius)"
+}
+
+// destinationPoint returns the point reached by travelling the given
+// distance along a great circle from a start point at the initial bearing
+func destinationPoint(lat, lon, bearing, distanceKm float64) (float64, float64) {
+	latRad := toRadians(lat)
+	lonRad := toRadians(lon)
+	bearingRad := toRadians(bearing)
+	angular := distanceKm / 6371.0
+
+	lat2 := math.Asin(math.Sin(latRad)*math.Cos(angular) +
+		math.Cos(latRad)*math.Sin(angular)*math.Cos(bearingRad))
+	lon2 := lonRad + math.Atan2(math.Sin(bearingRad)*math.Sin(angular)*math.Cos(latRad),
+		math.Cos(angular)-math.Sin(latRad)*math.Sin(lat2))
+
+	// Normalize longitude to -180..180
+	lon2Deg := math.Mod(toDegrees(lon2)+540, 360) - 180
+	return toDegrees(lat2), lon2Deg
+}
@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/azmap"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+)
+
+// defaultMapDestinations is how many destinations one map plots unless
+// configured otherwise
+const defaultMapDestinations = 20
+
+// Image formats accepted by the bearing map tool
+const (
+	mapFormatPNG = "png"
+	mapFormatSVG = "svg"
+)
+
+// RegisterBearingMapTool registers the bearing map tool with the MCP server.
+// entities may be nil, in which case callsigns without coordinates cannot be located.
+// The origin defaults to the station. maxDestinations limits how many destinations one map plots,
+// or is zero for the default.
+func RegisterBearingMapTool(s Registrar, provider lookup.CallsignProvider, entities *dxcc.Database, station *Station, maxDestinations int) {
+	if maxDestinations <= 0 {
+		maxDestinations = defaultMapDestinations
+	}
+
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Render an azimuthal equidistant map centered on the origin station, with compass rose, distance rings and great-circle paths to one or more destinations. The origin defaults to your station."),
+	}
+	options = append(options, placeOptions("origin", "Origin station")...)
+	options = append(options, placeOptions("destination", "Destination station")...)
+	options = append(options,
+		mcp.WithString("destinations",
+			mcp.Description(fmt.Sprintf("Additional destinations as a comma-separated list of grid squares or callsigns (up to %d); values that are valid grid squares are treated as grid squares", maxDestinations)),
+		),
+		mcp.WithString("format",
+			mcp.Description("Image format: png (default) or svg"),
+			mcp.Enum(mapFormatPNG, mapFormatSVG),
+		),
+		mcp.WithNumber("size",
+			mcp.Description(fmt.Sprintf("Width and height of the map in pixels (%d to %d, default %d)", azmap.MinSize, azmap.MaxSize, azmap.DefaultSize)),
+		),
+		outputOption(false),
+	)
+	tool := mcp.NewTool("bearing-map", options...)
+
+	// Add tool handler
+	s.AddTool(tool, BearingMap(provider, entities, station, maxDestinations))
+}
+
+// bearingMapResult is the JSON output of the bearing map tool, returned
+// alongside the image
+type bearingMapResult struct {
+	Center       *place           `json:"center"`
+	Destinations []mapDestination `json:"destinations"`
+}
+
+// mapDestination is a plotted destination with its short path from the center
+type mapDestination struct {
+	place
+	Bearing    float64 `json:"bearing"`
+	DistanceKm float64 `json:"distanceKm"`
+}
+
+// BearingMap returns a tool handler for rendering bearing maps
+func BearingMap(provider lookup.CallsignProvider, entities *dxcc.Database, station *Station, maxDestinations int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+
+		format, _ := args["format"].(string)
+		mimeType := "image/png"
+		switch format {
+		case "", mapFormatPNG:
+			format = mapFormatPNG
+		case mapFormatSVG:
+			mimeType = "image/svg+xml"
+		default:
+			return nil, fmt.Errorf("unknown format %q (expected %s or %s)", format, mapFormatPNG, mapFormatSVG)
+		}
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		origin, err := resolveOrigin(ctx, request, "origin", station, provider, entities)
+		if errors.Is(err, lookup.ErrNotFound) {
+			return mcp.NewToolResultText(fmt.Sprintf("Origin callsign %s is not valid", args["origin-callsign"])), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var destinations []azmap.Marker
+		if hasEndpoint(request, "destination") {
+			dest, err := resolvePlace(ctx, request, "destination", provider, entities)
+			if errors.Is(err, lookup.ErrNotFound) {
+				return mcp.NewToolResultText(fmt.Sprintf("Destination callsign %s is not valid", args["destination-callsign"])), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			destinations = append(destinations, azmap.Marker(*dest))
+		}
+
+		list, _ := args["destinations"].(string)
+		for _, item := range strings.Split(list, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			dest, err := parsePlace(ctx, item, provider, entities)
+			if errors.Is(err, lookup.ErrNotFound) {
+				return mcp.NewToolResultText(fmt.Sprintf("Destination callsign %s is not valid", strings.ToUpper(item))), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			destinations = append(destinations, azmap.Marker(*dest))
+		}
+
+		if len(destinations) == 0 {
+			return nil, errors.New("at least one destination must be given")
+		}
+		if len(destinations) > maxDestinations {
+			return nil, fmt.Errorf("at most %d destinations can be plotted", maxDestinations)
+		}
+
+		opts := azmap.Options{
+			Center:       azmap.Marker(*origin),
+			Destinations: destinations,
+			Size:         request.GetInt("size", azmap.DefaultSize),
+		}
+
+		var image []byte
+		if format == mapFormatSVG {
+			image, err = azmap.RenderSVG(opts)
+		} else {
+			image, err = azmap.RenderPNG(opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error rendering map: %v", err)
+		}
+
+		summary := bearingMapResult{Center: origin}
+		for _, dest := range destinations {
+			path := calculatePath(origin.Latitude, origin.Longitude, dest.Latitude, dest.Longitude, modelSpherical)
+			summary.Destinations = append(summary.Destinations, mapDestination{
+				place:      place(dest),
+				Bearing:    path.ShortPathBearing,
+				DistanceKm: path.ShortPathKm,
+			})
+		}
+		encoded := base64.StdEncoding.EncodeToString(image)
+
+		if output == outputJSON {
+			result, err := jsonResult(summary)
+			if err != nil {
+				return nil, err
+			}
+			result.Content = append(result.Content, mcp.NewImageContent(encoded, mimeType))
+			return result, nil
+		}
+
+		// Summarize the plotted paths alongside the image
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("## Bearing Map from %s\n\n", origin.Label))
+		result.WriteString(fmt.Sprintf("**Center:** %.4f, %.4f\n\n", origin.Latitude, origin.Longitude))
+		for _, dest := range summary.Destinations {
+			result.WriteString(fmt.Sprintf("- **%s:** %.1f degrees, %s\n",
+				dest.Label, dest.Bearing, station.formatDistance(dest.DistanceKm)))
+		}
+
+		return mcp.NewToolResultImage(result.String(), encoded, mimeType), nil
+	}
+}
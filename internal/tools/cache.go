@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/cache"
+)
+
+// RegisterCacheTools registers the cache administration tools with the MCP server
+func RegisterCacheTools(s Registrar, c *cache.Cache) {
+	statsTool := mcp.NewTool("cache-stats",
+		mcp.WithDescription("Show the hit ratio, entry count and time to live of each upstream response cache"),
+		outputOption(true),
+	)
+	s.AddTool(statsTool, CacheStats(c))
+
+	clearTool := mcp.NewTool("cache-clear",
+		mcp.WithDescription("Remove cached upstream responses so they are fetched again"),
+		mcp.WithString("source",
+			mcp.Description("Cache to clear (default all)"),
+			mcp.Enum(c.Sources()...),
+		),
+		outputOption(false),
+	)
+	s.AddTool(clearTool, CacheClear(c))
+}
+
+// cacheStatsResult is the JSON output of the cache statistics tool
+type cacheStatsResult struct {
+	Sources []cacheStatsRow `json:"sources"`
+}
+
+// cacheStatsRow describes one cache in the statistics
+type cacheStatsRow struct {
+	Source   string  `json:"source" csv:"source"`
+	TTL      string  `json:"ttl" csv:"ttl"`
+	Entries  int     `json:"entries" csv:"entries"`
+	Hits     uint64  `json:"hits" csv:"hits"`
+	Misses   uint64  `json:"misses" csv:"misses"`
+	HitRatio float64 `json:"hitRatio" csv:"hitRatio"`
+}
+
+// cacheClearResult is the JSON output of the cache clear tool. Source is
+// empty when every cache was cleared.
+type cacheClearResult struct {
+	Source  string `json:"source,omitempty"`
+	Removed int    `json:"removed"`
+}
+
+// CacheStats returns a tool handler for reporting cache statistics
+func CacheStats(c *cache.Cache) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		output, err := parseOutput(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := []cacheStatsRow{}
+		for _, s := range c.Stats() {
+			ttl := s.TTL.String()
+			if s.TTL <= 0 {
+				ttl = "disabled"
+			}
+			rows = append(rows, cacheStatsRow{
+				Source:   s.Source,
+				TTL:      ttl,
+				Entries:  s.Entries,
+				Hits:     s.Hits,
+				Misses:   s.Misses,
+				HitRatio: s.HitRatio(),
+			})
+		}
+
+		switch output {
+		case outputJSON:
+			return jsonResult(cacheStatsResult{Sources: rows})
+		case outputCSV:
+			return csvResult(rows)
+		}
+
+		var response strings.Builder
+		response.WriteString("## Cache Statistics\n\n")
+		response.WriteString("| Source | TTL | Entries | Hits | Misses | Hit Ratio |\n")
+		response.WriteString("|--------|-----|---------|------|--------|-----------|\n")
+		for _, row := range rows {
+			response.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d | %.1f%% |\n",
+				row.Source, row.TTL, row.Entries, row.Hits, row.Misses, row.HitRatio*100))
+		}
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
+
+// CacheClear returns a tool handler for clearing the cache
+func CacheClear(c *cache.Cache) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		source, _ := request.GetArguments()["source"].(string)
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		removed, err := c.Clear(source)
+		if err != nil {
+			return nil, err
+		}
+
+		if output == outputJSON {
+			return jsonResult(cacheClearResult{Source: source, Removed: removed})
+		}
+
+		if source == "" {
+			return mcp.NewToolResultText(fmt.Sprintf("Cleared %d cached entries from all caches", removed)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Cleared %d cached entries from the %s cache", removed, source)), nil
+	}
+}
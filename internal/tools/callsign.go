@@ -2,90 +2,148 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
 	"github.com/pleska/ham-radio-assistant/internal/models"
 )
 
 // RegisterCallsignLookupTool registers the callsign lookup tool with the MCP server
-func RegisterCallsignLookupTool(s *server.MCPServer) {
+func RegisterCallsignLookupTool(s Registrar, provider lookup.CallsignProvider) {
 	// Add tool
 	tool := mcp.NewTool("callsign-lookup",
-		mcp.WithDescription("Lookup a callsign using callook.info"),
+		mcp.WithDescription("Lookup a callsign using the configured callsign databases"),
 		mcp.WithString("callsign",
 			mcp.Required(),
-			mcp.Description("Amateur radio callsign"),
-			mcp.Pattern("^[A-Z0-9]{1,2}[0-9][A-Z]{1,3}$"),
+			mcp.Description("Amateur radio callsign, optionally with a prefix or suffix (e.g. W1AW, VE3/W1AW/P)"),
 		),
+		outputOption(false),
 	)
 
 	// Add tool handler
-	s.AddTool(tool, CallsignLookup)
+	s.AddTool(tool, CallsignLookup(provider))
 }
 
-// CallsignLookup is a tool handler for looking up amateur radio callsigns
-func CallsignLookup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	callsign, ok := request.Params.Arguments["callsign"].(string)
-	if !ok {
-		return nil, errors.New("callsign must be a string")
-	}
+// CallsignLookup returns a tool handler for looking up amateur radio callsigns
+func CallsignLookup(provider lookup.CallsignProvider) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		input, ok := request.GetArguments()["callsign"].(string)
+		if !ok {
+			return nil, errors.New("callsign must be a string")
+		}
 
-	// Make API request to callook.info
-	url := fmt.Sprintf("https://callook.info/%s/json", callsign)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("error making API request: %v", err)
-	}
-	defer resp.Body.Close()
+		call, err := callsign.Parse(input)
+		if err != nil {
+			return nil, err
+		}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
-	}
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
 
-	// Decode JSON response
-	var result models.CallsignResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("error parsing JSON response: %v", err)
-	}
+		// Databases only know the home callsign
+		result, err := provider.Lookup(ctx, call.Base)
+		if errors.Is(err, lookup.ErrNotFound) {
+			return mcp.NewToolResultText(fmt.Sprintf("Callsign %s is not valid", call)), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error looking up callsign: %v", err)
+		}
 
-	// Check if callsign is valid
-	if result.Status != "VALID" {
-		return mcp.NewToolResultText(fmt.Sprintf("Callsign %s is not valid", callsign)), nil
-	}
+		if output == outputJSON {
+			lookupResult := callsignResult{CallsignRecord: result}
+			if call.Full != call.Base {
+				lookupResult.OperatingAs = call.Full
+			}
+			return jsonResult(lookupResult)
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("## Callsign Information for %s\n\n", result.Callsign))
+		if result.LicenseClass != "" {
+			response.WriteString(fmt.Sprintf("**License Class:** %s\n", result.LicenseClass))
+		}
+		response.WriteString(fmt.Sprintf("**Name:** %s\n", result.Name))
+		if result.Type != "" {
+			response.WriteString(fmt.Sprintf("**Type:** %s\n", result.Type))
+		}
+		if call.Full != call.Base {
+			response.WriteString(fmt.Sprintf("**Operating As:** %s\n", describeCallsign(call)))
+		}
+		response.WriteString("\n")
+
+		response.WriteString("### Location\n")
+		response.WriteString(fmt.Sprintf("**Address:** %s, %s\n", result.AddressLine1, result.AddressLine2))
+		if result.Country != "" {
+			response.WriteString(fmt.Sprintf("**Country:** %s\n", result.Country))
+		}
+		response.WriteString(fmt.Sprintf("**Grid Square:** %s\n", result.Gridsquare))
+		if result.HasLocation {
+			response.WriteString(fmt.Sprintf("**Coordinates:** %f, %f\n", result.Latitude, result.Longitude))
+		}
+		response.WriteString("\n")
+
+		if result.GrantDate != "" || result.ExpiryDate != "" || result.Frn != "" {
+			response.WriteString("### License Information\n")
+			if result.GrantDate != "" {
+				response.WriteString(fmt.Sprintf("**Grant Date:** %s\n", result.GrantDate))
+			}
+			if result.ExpiryDate != "" {
+				response.WriteString(fmt.Sprintf("**Expiry Date:** %s\n", result.ExpiryDate))
+			}
+			if result.LastActionDate != "" {
+				response.WriteString(fmt.Sprintf("**Last Action Date:** %s\n", result.LastActionDate))
+			}
+			if result.Frn != "" {
+				response.WriteString(fmt.Sprintf("**FRN:** %s\n", result.Frn))
+			}
+		}
 
-	// Format response
-	var response strings.Builder
-	response.WriteString(fmt.Sprintf("## Callsign Information for %s\n\n", result.Current.Callsign))
-	response.WriteString(fmt.Sprintf("**License Class:** %s\n", result.Current.OperClass))
-	response.WriteString(fmt.Sprintf("**Name:** %s\n", result.Name))
-	response.WriteString(fmt.Sprintf("**Type:** %s\n\n", result.Type))
-
-	response.WriteString("### Location\n")
-	response.WriteString(fmt.Sprintf("**Address:** %s, %s\n", result.Address.Line1, result.Address.Line2))
-	response.WriteString(fmt.Sprintf("**Grid Square:** %s\n", result.Location.Gridsquare))
-	response.WriteString(fmt.Sprintf("**Coordinates:** %s, %s\n\n", result.Location.Latitude, result.Location.Longitude))
-
-	response.WriteString("### License Information\n")
-	response.WriteString(fmt.Sprintf("**Grant Date:** %s\n", result.OtherInfo.GrantDate))
-	response.WriteString(fmt.Sprintf("**Expiry Date:** %s\n", result.OtherInfo.ExpiryDate))
-	response.WriteString(fmt.Sprintf("**Last Action Date:** %s\n", result.OtherInfo.LastActionDate))
-	response.WriteString(fmt.Sprintf("**FRN:** %s\n", result.OtherInfo.Frn))
-
-	if result.Previous.Callsign != "" {
-		response.WriteString(fmt.Sprintf("\n**Previous Callsign:** %s (%s)\n",
-			result.Previous.Callsign, result.Previous.OperClass))
+		if result.PreviousCallsign != "" {
+			if result.PreviousClass != "" {
+				response.WriteString(fmt.Sprintf("\n**Previous Callsign:** %s (%s)\n",
+					result.PreviousCallsign, result.PreviousClass))
+			} else {
+				response.WriteString(fmt.Sprintf("\n**Previous Callsign:** %s\n", result.PreviousCallsign))
+			}
+		}
+
+		if result.URL != "" {
+			response.WriteString(fmt.Sprintf("\n[View on %s](%s)", result.Source, result.URL))
+		} else {
+			response.WriteString(fmt.Sprintf("\nData provided by %s", result.Source))
+		}
+
+		return mcp.NewToolResultText(response.String()), nil
 	}
+}
 
-	response.WriteString(fmt.Sprintf("\n[View on ULS](%s)", result.OtherInfo.UlsUrl))
+// callsignResult is the JSON output of the callsign lookup tool
+type callsignResult struct {
+	*models.CallsignRecord
+	// OperatingAs is the callsign as given when it has a prefix or suffix
+	OperatingAs string `json:"operatingAs,omitempty"`
+}
 
-	return mcp.NewToolResultText(response.String()), nil
+// describeCallsign describes a callsign with its prefix and modifier, e.g.
+// "VE3/W1AW/P (portable, operating prefix VE3)"
+func describeCallsign(call *callsign.Callsign) string {
+	var notes []string
+	if modifier := call.Modifier(); modifier != "" {
+		notes = append(notes, modifier)
+	}
+	if call.IsAway() {
+		notes = append(notes, fmt.Sprintf("operating prefix %s", call.OperatingPrefix()))
+	}
+	if len(notes) == 0 {
+		return call.Full
+	}
+	return fmt.Sprintf("%s (%s)", call.Full, strings.Join(notes, ", "))
 }
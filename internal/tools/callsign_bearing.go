@@ -2,141 +2,204 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/solar"
 )
 
-// RegisterCallsignBearingTool registers the callsign bearing tool with the MCP server
-func RegisterCallsignBearingTool(s *server.MCPServer) {
+// RegisterCallsignBearingTool registers the callsign bearing tool with the MCP server.
+// entities may be nil, in which case callsigns without coordinates cannot be located.
+// The origin defaults to the station.
+func RegisterCallsignBearingTool(s Registrar, provider lookup.CallsignProvider, entities *dxcc.Database, station *Station) {
 	// Add tool
 	tool := mcp.NewTool("callsign-bearing",
-		mcp.WithDescription("Calculate bearing between two callsigns"),
+		mcp.WithDescription("Calculate bearing between two callsigns and the gray-line windows they share over the next 24 hours"),
 		mcp.WithString("origin-callsign",
-			mcp.Required(),
-			mcp.Description("Your callsign"),
-			mcp.Pattern("^[A-Z0-9]{1,2}[0-9][A-Z]{1,3}$"),
+			mcp.Description("Origin callsign, optionally with a prefix or suffix (e.g. W1AW, W1AW/P); defaults to your station"),
 		),
 		mcp.WithString("destination-callsign",
 			mcp.Required(),
-			mcp.Description("Destination callsign"),
-			mcp.Pattern("^[A-Z0-9]{1,2}[0-9][A-Z]{1,3}$"),
+			mcp.Description("Destination callsign, optionally with a prefix or suffix (e.g. KH6/K1ABC)"),
 		),
+		modelOption(),
+		outputOption(false),
 	)
 
 	// Add tool handler
-	s.AddTool(tool, CallsignBearing)
+	s.AddTool(tool, CallsignBearing(provider, entities, station))
 }
 
-// CallsignBearing is a tool handler for calculating bearing between two callsigns
-func CallsignBearing(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	originCallsign, ok := request.Params.Arguments["origin-callsign"].(string)
-	if !ok {
-		return nil, errors.New("origin-callsign must be a string")
-	}
-
-	destCallsign, ok := request.Params.Arguments["destination-callsign"].(string)
-	if !ok {
-		return nil, errors.New("destination-callsign must be a string")
-	}
-
-	// Look up origin callsign
-	originInfo, err := lookupCallsign(originCallsign)
-	if err != nil {
-		return nil, fmt.Errorf("error looking up origin callsign: %v", err)
-	}
-	if originInfo.Status != "VALID" {
-		return mcp.NewToolResultText(fmt.Sprintf("Origin callsign %s is not valid", originCallsign)), nil
-	}
-
-	// Look up destination callsign
-	destInfo, err := lookupCallsign(destCallsign)
-	if err != nil {
-		return nil, fmt.Errorf("error looking up destination callsign: %v", err)
-	}
-	if destInfo.Status != "VALID" {
-		return mcp.NewToolResultText(fmt.Sprintf("Destination callsign %s is not valid", destCallsign)), nil
-	}
-
-	// Convert coordinates to float64
-	originLat, originLon, err := parseCoordinates(originInfo.Location.Latitude, originInfo.Location.Longitude)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing origin coordinates: %v", err)
-	}
-
-	destLat, destLon, err := parseCoordinates(destInfo.Location.Latitude, destInfo.Location.Longitude)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing destination coordinates: %v", err)
+// CallsignBearing returns a tool handler for calculating bearing between two callsigns
+func CallsignBearing(provider lookup.CallsignProvider, entities *dxcc.Database, station *Station) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		originInput, _ := request.GetArguments()["origin-callsign"].(string)
+
+		destInput, ok := request.GetArguments()["destination-callsign"].(string)
+		if !ok {
+			return nil, errors.New("destination-callsign must be a string")
+		}
+
+		destCallsign, err := callsign.Parse(destInput)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination callsign: %w", err)
+		}
+
+		model, err := parseModel(request)
+		if err != nil {
+			return nil, err
+		}
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Locate origin callsign, or your station when none is given
+		var origin *callsignLocation
+		var originName string
+		if originInput == "" {
+			if !station.Configured() {
+				return nil, errors.New("origin-callsign must be given when no station is configured")
+			}
+			if origin, err = station.locate(ctx); err != nil {
+				return nil, err
+			}
+			originName = station.Name()
+		} else {
+			originCallsign, err := callsign.Parse(originInput)
+			if err != nil {
+				return nil, fmt.Errorf("invalid origin callsign: %w", err)
+			}
+			origin, err = locateCallsign(ctx, provider, entities, originCallsign)
+			if errors.Is(err, lookup.ErrNotFound) {
+				return mcp.NewToolResultText(fmt.Sprintf("Origin callsign %s is not valid", originCallsign)), nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error looking up origin callsign: %v", err)
+			}
+			originName = originCallsign.Full
+		}
+
+		// Locate destination callsign
+		dest, err := locateCallsign(ctx, provider, entities, destCallsign)
+		if errors.Is(err, lookup.ErrNotFound) {
+			return mcp.NewToolResultText(fmt.Sprintf("Destination callsign %s is not valid", destCallsign)), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error looking up destination callsign: %v", err)
+		}
+
+		// Calculate short and long path
+		path := calculatePath(origin.Latitude, origin.Longitude, dest.Latitude, dest.Longitude, model)
+		windows := grayLineWindows(origin, dest)
+
+		if output == outputJSON {
+			return jsonResult(callsignBearingResult{
+				Origin:      labelledLocation{Label: originName, callsignLocation: origin},
+				Destination: labelledLocation{Label: destCallsign.Full, callsignLocation: dest},
+				Path:        path,
+				GrayLine:    windows,
+			})
+		}
+
+		// Format response
+		var result string
+		result = fmt.Sprintf("## Antenna Bearing: %s to %s\n\n", originName, destCallsign)
+		result += formatCallsignLocation(originName, origin)
+		result += formatCallsignLocation(destCallsign.Full, dest)
+		result += fmt.Sprintf("**Distance:** %s\n\n", station.formatDistance(path.ShortPathKm))
+		result += fmt.Sprintf("**Bearing:** %.1f degrees from North\n\n", path.ShortPathBearing)
+		result += formatLongPath(path, station) + "\n\n"
+		result += formatGrayLine(originName, destCallsign.Full, windows, station)
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	// Calculate distance and bearing
-	distanceKm, distanceMiles, bearing := calculateDistanceAndBearing(originLat, originLon, destLat, destLon)
+// callsignBearingResult is the JSON output of the callsign bearing tool
+type callsignBearingResult struct {
+	Origin      labelledLocation `json:"origin"`
+	Destination labelledLocation `json:"destination"`
+	Path        pathInfo         `json:"path"`
+	GrayLine    []solar.Window   `json:"grayLine"`
+}
 
-	// Format response
-	var result string
-	result = fmt.Sprintf("## Antenna Bearing: %s to %s\n\n", originCallsign, destCallsign)
-	result += fmt.Sprintf("**%s Location:** %s, %s\n", originCallsign, originInfo.Location.Latitude, originInfo.Location.Longitude)
-	result += fmt.Sprintf("**Grid Square:** %s\n\n", originInfo.Location.Gridsquare)
-	result += fmt.Sprintf("**%s Location:** %s, %s\n", destCallsign, destInfo.Location.Latitude, destInfo.Location.Longitude)
-	result += fmt.Sprintf("**Grid Square:** %s\n\n", destInfo.Location.Gridsquare)
-	result += fmt.Sprintf("**Distance:** %.2f miles (%.2f km)\n\n", distanceMiles, distanceKm)
-	result += fmt.Sprintf("**Bearing:** %.1f degrees from North", bearing)
+// labelledLocation is a callsign location with the name it is shown under
+type labelledLocation struct {
+	Label string `json:"label"`
+	*callsignLocation
+}
 
-	return mcp.NewToolResultText(result), nil
+// callsignLocation is the resolved position of a callsign
+type callsignLocation struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Gridsquare string  `json:"gridsquare,omitempty"`
+	// Approximation describes where an approximate location came from and
+	// is empty when the provider supplied coordinates
+	Approximation string `json:"approximation,omitempty"`
 }
 
-// lookupCallsign looks up a callsign using the callook.info API
-func lookupCallsign(callsign string) (*models.CallsignResponse, error) {
-	// Make API request to callook.info
-	url := fmt.Sprintf("https://callook.info/%s/json", callsign)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("error making API request: %v", err)
+// locateCallsign looks up the location of a callsign, falling back to the
+// centroid of its DXCC entity when the provider has no coordinates or the
+// lookup fails. A station operating away from home under another entity's
+// prefix (e.g. KH6/K1ABC) is placed at that entity's centroid rather than
+// its home address. lookup.ErrNotFound is returned when neither source
+// knows the callsign.
+func locateCallsign(ctx context.Context, provider lookup.CallsignProvider, entities *dxcc.Database, call *callsign.Callsign) (*callsignLocation, error) {
+	if call.IsMaritimeMobile() || call.IsAeronauticalMobile() {
+		return nil, fmt.Errorf("%s is operating %s and has no fixed location", call, call.Modifier())
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
+	var operating *dxcc.Match
+	if entities != nil {
+		operating, _ = entities.Lookup(call.Full)
 	}
 
-	// Decode JSON response
-	var result models.CallsignResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("error parsing JSON response: %v", err)
+	if call.IsAway() && operating != nil {
+		if home, ok := entities.Lookup(call.Base); !ok || home.Entity != operating.Entity {
+			return &callsignLocation{
+				Latitude:      operating.Latitude,
+				Longitude:     operating.Longitude,
+				Approximation: fmt.Sprintf("operating from DXCC entity %s", operating.Entity.Name),
+			}, nil
+		}
 	}
 
-	return &result, nil
-}
+	record, err := provider.Lookup(ctx, call.Base)
+	if err == nil && record.HasLocation {
+		return &callsignLocation{
+			Latitude:   record.Latitude,
+			Longitude:  record.Longitude,
+			Gridsquare: record.Gridsquare,
+		}, nil
+	}
 
-// parseCoordinates parses latitude and longitude strings to float64
-func parseCoordinates(lat, lon string) (float64, float64, error) {
-	latitude, err := parseCoordinate(lat)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid latitude: %v", err)
+	if operating != nil {
+		return &callsignLocation{
+			Latitude:      operating.Latitude,
+			Longitude:     operating.Longitude,
+			Approximation: fmt.Sprintf("centroid of DXCC entity %s", operating.Entity.Name),
+		}, nil
 	}
 
-	longitude, err := parseCoordinate(lon)
 	if err != nil {
-		return 0, 0, fmt.Errorf("invalid longitude: %v", err)
+		return nil, err
 	}
-
-	return latitude, longitude, nil
+	return nil, fmt.Errorf("no location is available for %s", call)
 }
 
-// parseCoordinate parses a coordinate string to float64
-func parseCoordinate(coord string) (float64, error) {
-	var result float64
-	_, err := fmt.Sscanf(coord, "%f", &result)
-	if err != nil {
-		return 0, err
+// formatCallsignLocation formats the location lines for a callsign
+func formatCallsignLocation(callsign string, location *callsignLocation) string {
+	result := fmt.Sprintf("**%s Location:** %f, %f\n", callsign, location.Latitude, location.Longitude)
+	if location.Approximation != "" {
+		result += fmt.Sprintf("**Approximate:** %s\n\n", location.Approximation)
+		return result
 	}
-	return result, nil
+	return result + fmt.Sprintf("**Grid Square:** %s\n\n", location.Gridsquare)
 }
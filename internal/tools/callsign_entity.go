@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+)
+
+// RegisterCallsignEntityTool registers the DXCC entity lookup tool with the MCP server
+func RegisterCallsignEntityTool(s Registrar, entities *dxcc.Database) {
+	// Add tool
+	tool := mcp.NewTool("callsign-entity",
+		mcp.WithDescription("Identify the DXCC entity (country), continent, CQ zone and ITU zone of a callsign from its prefix"),
+		mcp.WithString("callsign",
+			mcp.Required(),
+			mcp.Description("Amateur radio callsign or prefix, optionally with a prefix or suffix (e.g. KH6/K1ABC)"),
+		),
+		outputOption(false),
+	)
+
+	// Add tool handler
+	s.AddTool(tool, CallsignEntity(entities))
+}
+
+// entityResult is the JSON output of the DXCC entity lookup tool
+type entityResult struct {
+	Callsign string `json:"callsign"`
+	*dxcc.Match
+}
+
+// CallsignEntity returns a tool handler for resolving a callsign to its DXCC entity
+func CallsignEntity(entities *dxcc.Database) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		input, ok := request.GetArguments()["callsign"].(string)
+		if !ok {
+			return nil, errors.New("callsign must be a string")
+		}
+		input = strings.ToUpper(strings.TrimSpace(input))
+
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Bare prefixes are not valid callsigns but can still be resolved
+		if call, err := callsign.Parse(input); err == nil && call.OperatingPrefix() == "" {
+			return mcp.NewToolResultText(fmt.Sprintf("%s is operating %s and has no DXCC entity", call, call.Modifier())), nil
+		}
+
+		match, ok := entities.Lookup(input)
+		if !ok {
+			return mcp.NewToolResultText(fmt.Sprintf("No DXCC entity found for %s", input)), nil
+		}
+
+		if output == outputJSON {
+			return jsonResult(entityResult{Callsign: input, Match: match})
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("## DXCC Entity for %s\n\n", input))
+		response.WriteString(fmt.Sprintf("**Entity:** %s\n", match.Entity.Name))
+		response.WriteString(fmt.Sprintf("**Primary Prefix:** %s\n", match.Entity.PrimaryPrefix))
+		if match.Entity.DXCC != 0 {
+			response.WriteString(fmt.Sprintf("**DXCC Number:** %d\n", match.Entity.DXCC))
+		}
+		if match.Entity.WAEOnly {
+			response.WriteString("**Note:** Counts for the WAE/CQ country lists only, not DXCC\n")
+		}
+		response.WriteString(fmt.Sprintf("**Continent:** %s\n", match.Continent))
+		response.WriteString(fmt.Sprintf("**CQ Zone:** %d\n", match.CQZone))
+		response.WriteString(fmt.Sprintf("**ITU Zone:** %d\n", match.ITUZone))
+		response.WriteString(fmt.Sprintf("**Coordinates:** %.2f, %.2f\n", match.Latitude, match.Longitude))
+		response.WriteString(fmt.Sprintf("**UTC Offset:** %+.1f hours\n\n", match.UTCOffset))
+
+		if match.Exact {
+			response.WriteString("Matched by an exact callsign entry")
+		} else {
+			response.WriteString(fmt.Sprintf("Matched by prefix %s", match.Prefix))
+		}
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+)
+
+// RegisterGridDistanceTool registers the grid square distance tool with the MCP server.
+// The origin square defaults to the station's.
+func RegisterGridDistanceTool(s Registrar, station *Station) {
+	// Add tool
+	tool := mcp.NewTool("grid-distance",
+		mcp.WithDescription("Calculate distance and bearing between two Maidenhead grid squares"),
+		mcp.WithString("from-grid",
+			mcp.Description("Origin grid square, 2 to 10 characters (e.g. FN31pr); defaults to your station's grid square"),
+		),
+		mcp.WithString("to-grid",
+			mcp.Required(),
+			mcp.Description("Destination grid square, 2 to 10 characters (e.g. JO62qm)"),
+		),
+		outputOption(false),
+	)
+
+	// Add tool handler
+	s.AddTool(tool, GridDistance(station))
+}
+
+// GridDistance returns a tool handler for calculating distance and bearing between grid squares
+func GridDistance(station *Station) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		fromGrid, _ := request.GetArguments()["from-grid"].(string)
+		if fromGrid == "" {
+			if !station.Configured() {
+				return nil, errors.New("from-grid must be given when no station is configured")
+			}
+			origin, err := station.endpoint(ctx)
+			if err != nil {
+				return nil, err
+			}
+			fromGrid = origin.Grid
+		}
+
+		toGrid, ok := request.GetArguments()["to-grid"].(string)
+		if !ok {
+			return nil, errors.New("to-grid must be a string")
+		}
+
+		from, err := maidenhead.Parse(fromGrid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from-grid: %v", err)
+		}
+		to, err := maidenhead.Parse(toGrid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to-grid: %v", err)
+		}
+
+		// Calculate distance and bearing between the square centers
+		fromLat, fromLon := from.Center()
+		toLat, toLon := to.Center()
+		distanceKm, _, bearing := calculateDistanceAndBearing(fromLat, fromLon, toLat, toLon)
+
+		if output == outputJSON {
+			return jsonResult(gridDistanceResult{From: from, To: to, DistanceKm: distanceKm, Bearing: bearing})
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("## Grid Distance: %s to %s\n\n", from.Locator, to.Locator))
+		response.WriteString(formatSquare(from))
+		response.WriteString(formatSquare(to))
+		response.WriteString(fmt.Sprintf("**Distance:** %s\n\n", station.formatDistance(distanceKm)))
+		response.WriteString(fmt.Sprintf("**Bearing:** %.1f degrees from North\n\n", bearing))
+		response.WriteString("Distances are measured between the centers of the squares")
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
+
+// gridDistanceResult is the JSON output of the grid distance tool
+type gridDistanceResult struct {
+	From       maidenhead.Square `json:"from"`
+	To         maidenhead.Square `json:"to"`
+	DistanceKm float64           `json:"distanceKm"`
+	Bearing    float64           `json:"bearing"`
+}
+
+// formatSquare formats the center and bounding box of a grid square
+func formatSquare(square maidenhead.Square) string {
+	lat, lon := square.Center()
+	return fmt.Sprintf("### %s\n"+
+		"**Center:** %.4f, %.4f\n"+
+		"**Bounds:** %.4f to %.4f latitude, %.4f to %.4f longitude\n\n",
+		square.Locator, lat, lon, square.South, square.North, square.West, square.East)
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Output formats accepted by the output parameter
+const (
+	outputMarkdown = "markdown"
+	outputJSON     = "json"
+	outputCSV      = "csv"
+)
+
+// outputOption returns the tool parameter selecting the output format.
+// Tabular tools also accept csv.
+func outputOption(tabular bool) mcp.ToolOption {
+	if tabular {
+		return mcp.WithString("output",
+			mcp.Description("Output format: markdown (default), json (also returned as structured content) or csv (the rows of the table only)"),
+			mcp.Enum(outputMarkdown, outputJSON, outputCSV),
+		)
+	}
+	return mcp.WithString("output",
+		mcp.Description("Output format: markdown (default) or json (also returned as structured content)"),
+		mcp.Enum(outputMarkdown, outputJSON),
+	)
+}
+
+// parseOutput reads the output format from the request
+func parseOutput(request mcp.CallToolRequest, tabular bool) (string, error) {
+	output, _ := request.GetArguments()["output"].(string)
+	switch output {
+	case "", outputMarkdown:
+		return outputMarkdown, nil
+	case outputJSON:
+		return outputJSON, nil
+	case outputCSV:
+		if tabular {
+			return outputCSV, nil
+		}
+		return "", fmt.Errorf("unknown output %q (expected %s or %s)", output, outputMarkdown, outputJSON)
+	default:
+		if tabular {
+			return "", fmt.Errorf("unknown output %q (expected %s, %s or %s)", output, outputMarkdown, outputJSON, outputCSV)
+		}
+		return "", fmt.Errorf("unknown output %q (expected %s or %s)", output, outputMarkdown, outputJSON)
+	}
+}
+
+// jsonResult returns a value as indented JSON text, with the value itself as
+// the structured content of the result. The value must encode as a JSON
+// object.
+func jsonResult(value any) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding result: %v", err)
+	}
+	return mcp.NewToolResultStructured(value, string(data)), nil
+}
+
+// csvResult returns a slice of structs as CSV text with a header row, using
+// the csv tags of the struct fields as column names
+func csvResult(rows any) (*mcp.CallToolResult, error) {
+	data, err := gocsv.MarshalString(rows)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding result: %v", err)
+	}
+	return mcp.NewToolResultText(data), nil
+}
@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+)
+
+const (
+	// maxF2HopKm is the usual maximum ground distance of a single F2 hop
+	maxF2HopKm = 4000.0
+	// profileStepKm is the sampling interval used to find grid squares
+	profileStepKm = 25.0
+	// defaultWaypoints is how many waypoints are listed when none are requested
+	defaultWaypoints = 10
+	// defaultMaxWaypoints is how many waypoints can be listed unless
+	// configured otherwise
+	defaultMaxWaypoints = 100
+)
+
+// RegisterPathProfileTool registers the great-circle path profile tool with the MCP server.
+// The origin defaults to the station. maxWaypoints limits how many waypoints can be listed,
+// or is zero for the default.
+func RegisterPathProfileTool(s Registrar, station *Station, maxWaypoints int) {
+	if maxWaypoints <= 0 {
+		maxWaypoints = defaultMaxWaypoints
+	}
+
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Profile the great-circle path between two points: waypoints, midpoint, F2 hops and the grid squares crossed. The origin defaults to your station."),
+	}
+	options = append(options, endpointOptions("origin", "Origin station")...)
+	options = append(options, endpointOptions("destination", "Destination station")...)
+	options = append(options,
+		mcp.WithNumber("waypoints",
+			mcp.Description(fmt.Sprintf("Number of evenly spaced waypoints to list, including both ends (default %d)", min(defaultWaypoints, maxWaypoints))),
+			mcp.DefaultNumber(float64(min(defaultWaypoints, maxWaypoints))),
+			mcp.Min(2),
+			mcp.Max(float64(maxWaypoints)),
+		),
+		mcp.WithBoolean("long-path",
+			mcp.Description("Profile the long path instead of the short path"),
+		),
+		outputOption(true),
+	)
+	tool := mcp.NewTool("path-profile", options...)
+
+	// Add tool handler
+	s.AddTool(tool, PathProfile(station, maxWaypoints))
+}
+
+// PathProfile returns a tool handler for profiling the great-circle path between two points
+func PathProfile(station *Station, maxWaypoints int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		origin, err := originEndpoint(ctx, request, station)
+		if err != nil {
+			return nil, err
+		}
+		dest, err := parseEndpoint(request, "destination")
+		if err != nil {
+			return nil, err
+		}
+		waypoints := request.GetInt("waypoints", min(defaultWaypoints, maxWaypoints))
+		if waypoints < 2 || waypoints > maxWaypoints {
+			return nil, fmt.Errorf("waypoints must be between 2 and %d", maxWaypoints)
+		}
+		longPath := request.GetBool("long-path", false)
+		output, err := parseOutput(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		path := calculatePath(origin.Latitude, origin.Longitude, dest.Latitude, dest.Longitude, modelSpherical)
+		profile := pathProfileResult{
+			Origin:      origin,
+			Destination: dest,
+			LongPath:    longPath,
+			DistanceKm:  path.ShortPathKm,
+			Bearing:     path.ShortPathBearing,
+		}
+		label := "Short Path"
+		if longPath {
+			profile.DistanceKm, profile.Bearing, label = path.LongPathKm, path.LongPathBearing, "Long Path"
+		}
+
+		// pointAt returns the point at a distance along the path
+		pointAt := func(km float64) pathPoint {
+			lat, lon := destinationPoint(origin.Latitude, origin.Longitude, profile.Bearing, km)
+			grid, _ := maidenhead.Encode(lat, lon, 6)
+			return pathPoint{DistanceKm: km, Latitude: lat, Longitude: lon, Grid: grid}
+		}
+
+		// Midpoint and F2 hops
+		profile.Midpoint = pointAt(profile.DistanceKm / 2)
+		profile.Hops = max(1, int(math.Ceil(profile.DistanceKm/maxF2HopKm)))
+		profile.HopKm = profile.DistanceKm / float64(profile.Hops)
+		if profile.Hops > 1 {
+			for i := 0; i < profile.Hops; i++ {
+				profile.Reflections = append(profile.Reflections, pointAt(profile.HopKm*(float64(i)+0.5)))
+			}
+		}
+
+		// Waypoints
+		for i := 0; i < waypoints; i++ {
+			profile.Waypoints = append(profile.Waypoints, pointAt(profile.DistanceKm*float64(i)/float64(waypoints-1)))
+		}
+
+		// Sample the path for grid squares
+		profile.Squares = pathSquares(profile.DistanceKm, pointAt)
+
+		switch output {
+		case outputJSON:
+			return jsonResult(profile)
+		case outputCSV:
+			return csvResult(profile.Waypoints)
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("## Path Profile (%s)\n\n", label))
+		response.WriteString(fmt.Sprintf("**Origin:** %s\n", origin))
+		response.WriteString(fmt.Sprintf("**Destination:** %s\n\n", dest))
+		response.WriteString(fmt.Sprintf("**Distance:** %s\n", station.formatDistance(profile.DistanceKm)))
+		response.WriteString(fmt.Sprintf("**Bearing:** %.1f degrees from North\n\n", profile.Bearing))
+
+		response.WriteString(fmt.Sprintf("**Midpoint:** %s\n", profile.Midpoint))
+		response.WriteString(fmt.Sprintf("**F2 Hops:** %d (%.0f km per hop)\n\n", profile.Hops, profile.HopKm))
+		if len(profile.Reflections) > 0 {
+			response.WriteString("### Hop Reflection Points\n")
+			for i, point := range profile.Reflections {
+				response.WriteString(fmt.Sprintf("%d. %s\n", i+1, point))
+			}
+			response.WriteString("\n")
+		}
+
+		response.WriteString("### Waypoints\n")
+		response.WriteString("| # | Distance (km) | Latitude | Longitude | Grid |\n")
+		response.WriteString("|---|---------------|----------|-----------|------|\n")
+		for i, point := range profile.Waypoints {
+			response.WriteString(fmt.Sprintf("| %d | %.0f | %.4f | %.4f | %s |\n",
+				i+1, point.DistanceKm, point.Latitude, point.Longitude, point.Grid))
+		}
+		response.WriteString("\n")
+
+		response.WriteString(fmt.Sprintf("### Grid Squares Crossed (%d)\n", len(profile.Squares)))
+		response.WriteString(strings.Join(profile.Squares, ", "))
+		response.WriteString("\n")
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
+
+// pathProfileResult is the JSON output of the path profile tool. Its
+// waypoints are the CSV output.
+type pathProfileResult struct {
+	Origin      *endpoint   `json:"origin"`
+	Destination *endpoint   `json:"destination"`
+	LongPath    bool        `json:"longPath"`
+	DistanceKm  float64     `json:"distanceKm"`
+	Bearing     float64     `json:"bearing"`
+	Midpoint    pathPoint   `json:"midpoint"`
+	Hops        int         `json:"hops"`
+	HopKm       float64     `json:"hopKm"`
+	Reflections []pathPoint `json:"reflections,omitempty"`
+	Waypoints   []pathPoint `json:"waypoints"`
+	Squares     []string    `json:"squares"`
+}
+
+// pathPoint is a point along a path
+type pathPoint struct {
+	DistanceKm float64 `json:"distanceKm" csv:"distanceKm"`
+	Latitude   float64 `json:"latitude" csv:"latitude"`
+	Longitude  float64 `json:"longitude" csv:"longitude"`
+	Grid       string  `json:"grid" csv:"grid"`
+}
+
+// String formats the point as coordinates with its grid square
+func (p pathPoint) String() string {
+	return fmt.Sprintf("%.4f, %.4f (%s)", p.Latitude, p.Longitude, p.Grid)
+}
+
+// pathSquares walks the path in small steps and returns the 4-character
+// grid squares crossed, in path order
+func pathSquares(distanceKm float64, pointAt func(km float64) pathPoint) []string {
+	var squares []string
+	seen := make(map[string]bool)
+
+	steps := int(math.Ceil(distanceKm / profileStepKm))
+	for i := 0; i <= steps; i++ {
+		point := pointAt(math.Min(float64(i)*profileStepKm, distanceKm))
+		if square, err := maidenhead.Encode(point.Latitude, point.Longitude, 4); err == nil && !seen[square] {
+			seen[square] = true
+			squares = append(squares, square)
+		}
+	}
+
+	return squares
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+)
+
+// place is a labelled location resolved from tool arguments
+type place struct {
+	Label     string  `json:"label"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// placeOptions returns the tool parameters describing a place given as a
+// callsign, a grid square or coordinates
+func placeOptions(name, label string) []mcp.ToolOption {
+	options := []mcp.ToolOption{
+		mcp.WithString(name+"-callsign",
+			mcp.Description(label+" callsign, used in place of coordinates or a grid square"),
+		),
+	}
+	return append(options, endpointOptions(name, label)...)
+}
+
+// hasEndpoint reports whether any parameter describing the named end of a
+// path was given
+func hasEndpoint(request mcp.CallToolRequest, name string) bool {
+	args := request.GetArguments()
+	for _, suffix := range []string{"-callsign", "-grid", "-latitude", "-longitude"} {
+		if v, _ := args[name+suffix].(string); v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePlace reads the named end of a path from the request as a callsign,
+// a grid square or coordinates, in that order of precedence
+func resolvePlace(ctx context.Context, request mcp.CallToolRequest, name string, provider lookup.CallsignProvider, entities *dxcc.Database) (*place, error) {
+	if input, _ := request.GetArguments()[name+"-callsign"].(string); input != "" {
+		call, err := callsign.Parse(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s callsign: %w", name, err)
+		}
+		return callsignPlace(ctx, call, provider, entities)
+	}
+
+	e, err := parseEndpoint(request, name)
+	if err != nil {
+		return nil, err
+	}
+	return &place{Label: e.Grid, Latitude: e.Latitude, Longitude: e.Longitude}, nil
+}
+
+// resolveOrigin reads the named origin from the request, defaulting to your
+// station when no origin parameter is given
+func resolveOrigin(ctx context.Context, request mcp.CallToolRequest, name string, station *Station, provider lookup.CallsignProvider, entities *dxcc.Database) (*place, error) {
+	if !hasEndpoint(request, name) {
+		if !station.Configured() {
+			return nil, fmt.Errorf("%s must be given when no station is configured", name)
+		}
+		return station.place(ctx)
+	}
+	return resolvePlace(ctx, request, name, provider, entities)
+}
+
+// parsePlace resolves a value that may be either a grid square or a
+// callsign, preferring the grid square when it is valid as both
+func parsePlace(ctx context.Context, item string, provider lookup.CallsignProvider, entities *dxcc.Database) (*place, error) {
+	if square, err := maidenhead.Parse(item); err == nil {
+		lat, lon := square.Center()
+		return &place{Label: square.Locator, Latitude: lat, Longitude: lon}, nil
+	}
+
+	call, err := callsign.Parse(item)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a grid square nor a callsign", item)
+	}
+	return callsignPlace(ctx, call, provider, entities)
+}
+
+// callsignPlace locates a callsign
+func callsignPlace(ctx context.Context, call *callsign.Callsign, provider lookup.CallsignProvider, entities *dxcc.Database) (*place, error) {
+	location, err := locateCallsign(ctx, provider, entities, call)
+	if err != nil {
+		if errors.Is(err, lookup.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error looking up callsign %s: %w", call, err)
+	}
+	return &place{Label: call.Full, Latitude: location.Latitude, Longitude: location.Longitude}, nil
+}
@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/pota"
+)
+
+// defaultParkSearchResults is how many parks a search lists unless configured
+const defaultParkSearchResults = 20
+
+// RegisterPotaParkSearchTool registers the offline POTA park search tool with
+// the MCP server. limit is the number of parks listed when the call does not
+// ask for a number, or zero for the default.
+func RegisterPotaParkSearchTool(s Registrar, catalog *pota.Catalog, limit int) {
+	if limit <= 0 {
+		limit = defaultParkSearchResults
+	}
+
+	// Add tool
+	tool := mcp.NewTool("pota-park-search",
+		mcp.WithDescription("Search the offline Parks on the Air (POTA) park list by name, location, park type and active status"),
+		mcp.WithString("name",
+			mcp.Description("Park name or part of it; misspellings and partial words are allowed (e.g., rocky mountain)"),
+		),
+		mcp.WithString("location",
+			mcp.Description("Location code (e.g., US-CO, VE-ON) or reference prefix (e.g., US, G)"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Kind of park, matched against the park name (e.g., State Park, National Forest, Wildlife Area)"),
+		),
+		mcp.WithBoolean("active",
+			mcp.Description("true for active parks only, false for inactive parks only; both when omitted"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of parks to list (default %d)", limit)),
+			mcp.Min(1),
+		),
+		outputOption(true),
+	)
+
+	// Add tool handler
+	s.AddTool(tool, PotaParkSearch(catalog, limit))
+}
+
+// parkRow is a park in the search results
+type parkRow struct {
+	Reference string  `json:"reference" csv:"reference"`
+	Name      string  `json:"name" csv:"name"`
+	Location  string  `json:"location" csv:"location"`
+	Grid      string  `json:"grid" csv:"grid"`
+	Latitude  float64 `json:"latitude" csv:"latitude"`
+	Longitude float64 `json:"longitude" csv:"longitude"`
+	Active    bool    `json:"active" csv:"active"`
+}
+
+// newParkRow returns the search result row of a park
+func newParkRow(park models.ParkReference) parkRow {
+	return parkRow{
+		Reference: park.Reference,
+		Name:      park.Name,
+		Location:  park.LocationDesc,
+		Grid:      park.Grid6,
+		Latitude:  park.Latitude,
+		Longitude: park.Longitude,
+		Active:    park.IsActive(),
+	}
+}
+
+// parkSearchResult is the JSON output of the park search tool
+type parkSearchResult struct {
+	Matched int       `json:"matched"`
+	Parks   []parkRow `json:"parks"`
+}
+
+// PotaParkSearch returns a tool handler for searching the offline park catalog
+func PotaParkSearch(catalog *pota.Catalog, limit int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		var query pota.Query
+		query.Name, _ = args["name"].(string)
+		query.Location, _ = args["location"].(string)
+		query.Type, _ = args["type"].(string)
+		if _, ok := args["active"]; ok {
+			active := request.GetBool("active", true)
+			query.Active = &active
+		}
+		if strings.TrimSpace(query.Name+query.Location+query.Type) == "" {
+			return nil, errors.New("at least one of name, location or type must be given")
+		}
+
+		count := request.GetInt("limit", limit)
+		if count < 1 {
+			return nil, errors.New("limit must be at least 1")
+		}
+		output, err := parseOutput(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		parks := catalog.Search(query)
+		matched := len(parks)
+		rows := make([]parkRow, 0, min(count, matched))
+		for _, park := range parks[:min(count, matched)] {
+			rows = append(rows, newParkRow(park))
+		}
+
+		switch output {
+		case outputJSON:
+			return jsonResult(parkSearchResult{Matched: matched, Parks: rows})
+		case outputCSV:
+			return csvResult(rows)
+		}
+
+		if len(rows) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No POTA parks found matching %s", describeParkQuery(query))), nil
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString("# POTA Park Search\n\n")
+		response.WriteString(fmt.Sprintf("Parks matching %s\n\n", describeParkQuery(query)))
+		if matched > len(rows) {
+			response.WriteString(fmt.Sprintf("Showing %d of %d parks\n\n", len(rows), matched))
+		}
+
+		response.WriteString("| Reference | Name | Location | Grid | Status |\n")
+		response.WriteString("|-----------|------|----------|------|--------|\n")
+		for _, row := range rows {
+			response.WriteString(fmt.Sprintf("| [%s](https://pota.app/#/park/%s) | %s | %s | %s | %s |\n",
+				row.Reference,
+				row.Reference,
+				row.Name,
+				row.Location,
+				row.Grid,
+				formatStatus(row.Active),
+			))
+		}
+
+		response.WriteString("\n\nPark list from [Parks on the Air](https://pota.app)")
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
+
+// describeParkQuery describes the filters of a park search in Markdown
+func describeParkQuery(query pota.Query) string {
+	var filters []string
+	if query.Name != "" {
+		filters = append(filters, fmt.Sprintf("name **%s**", query.Name))
+	}
+	if query.Location != "" {
+		filters = append(filters, fmt.Sprintf("location **%s**", strings.ToUpper(query.Location)))
+	}
+	if query.Type != "" {
+		filters = append(filters, fmt.Sprintf("type **%s**", query.Type))
+	}
+	if query.Active != nil {
+		filters = append(filters, fmt.Sprintf("status **%s**", formatStatus(*query.Active)))
+	}
+	return strings.Join(filters, ", ")
+}
@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/pota"
+)
+
+// defaultNearbyRadiusKm is the search radius when none is given
+const defaultNearbyRadiusKm = 50
+
+// Units accepted for the nearby parks radius
+const (
+	radiusKm    = "km"
+	radiusMiles = "miles"
+)
+
+// RegisterPotaParksNearbyTool registers the nearby POTA parks tool with the
+// MCP server. The origin defaults to the station. limit is the number of
+// parks listed when the call does not ask for a number, or zero for the
+// default.
+func RegisterPotaParksNearbyTool(s Registrar, catalog *pota.Catalog, provider lookup.CallsignProvider, entities *dxcc.Database, station *Station, limit int) {
+	if limit <= 0 {
+		limit = defaultParkSearchResults
+	}
+
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Find POTA parks within a radius of a location, sorted by distance with the bearing to each park. The origin defaults to your station."),
+	}
+	options = append(options, placeOptions("origin", "Origin")...)
+	options = append(options,
+		mcp.WithNumber("radius",
+			mcp.Description(fmt.Sprintf("Search radius (default %d km)", defaultNearbyRadiusKm)),
+			mcp.Min(0),
+		),
+		mcp.WithString("units",
+			mcp.Description("Units of the radius: km or miles (default the station's units)"),
+			mcp.Enum(radiusKm, radiusMiles),
+		),
+		mcp.WithBoolean("include-inactive",
+			mcp.Description("Also list parks that are no longer active (default false)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of parks to list (default %d)", limit)),
+			mcp.Min(1),
+		),
+		outputOption(true),
+	)
+	tool := mcp.NewTool("pota-parks-nearby", options...)
+
+	// Add tool handler
+	s.AddTool(tool, PotaParksNearby(catalog, provider, entities, station, limit))
+}
+
+// nearbyPark is a park in the nearby parks results, with the short path to
+// it from the origin
+type nearbyPark struct {
+	Reference  string  `json:"reference" csv:"reference"`
+	Name       string  `json:"name" csv:"name"`
+	Location   string  `json:"location" csv:"location"`
+	Grid       string  `json:"grid" csv:"grid"`
+	Latitude   float64 `json:"latitude" csv:"latitude"`
+	Longitude  float64 `json:"longitude" csv:"longitude"`
+	Active     bool    `json:"active" csv:"active"`
+	DistanceKm float64 `json:"distanceKm" csv:"distanceKm"`
+	Bearing    float64 `json:"bearing" csv:"bearing"`
+}
+
+// parksNearbyResult is the JSON output of the nearby parks tool
+type parksNearbyResult struct {
+	Origin   *place       `json:"origin"`
+	RadiusKm float64      `json:"radiusKm"`
+	Matched  int          `json:"matched"`
+	Parks    []nearbyPark `json:"parks"`
+}
+
+// PotaParksNearby returns a tool handler for finding parks near a location
+func PotaParksNearby(catalog *pota.Catalog, provider lookup.CallsignProvider, entities *dxcc.Database, station *Station, limit int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+
+		units, _ := args["units"].(string)
+		switch units {
+		case "":
+			units = radiusMiles
+			if station == nil || station.Metric {
+				units = radiusKm
+			}
+		case radiusKm, radiusMiles:
+		default:
+			return nil, fmt.Errorf("unknown units %q (expected %s or %s)", units, radiusKm, radiusMiles)
+		}
+
+		radius := request.GetFloat("radius", 0)
+		radiusKm := radius
+		if units == radiusMiles {
+			radiusKm = radius / kmToMiles
+		}
+		if _, ok := args["radius"]; !ok {
+			radiusKm = defaultNearbyRadiusKm
+		}
+		if radiusKm <= 0 {
+			return nil, errors.New("radius must be greater than zero")
+		}
+
+		count := request.GetInt("limit", limit)
+		if count < 1 {
+			return nil, errors.New("limit must be at least 1")
+		}
+		includeInactive := request.GetBool("include-inactive", false)
+		output, err := parseOutput(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		origin, err := resolveOrigin(ctx, request, "origin", station, provider, entities)
+		if errors.Is(err, lookup.ErrNotFound) {
+			return mcp.NewToolResultText(fmt.Sprintf("Origin callsign %s is not valid", args["origin-callsign"])), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var parks []nearbyPark
+		for _, park := range catalog.Parks() {
+			if !includeInactive && !park.IsActive() {
+				continue
+			}
+			// Parks without coordinates are listed at 0, 0
+			if park.Latitude == 0 && park.Longitude == 0 {
+				continue
+			}
+			distanceKm, _, bearing := calculateDistanceAndBearing(origin.Latitude, origin.Longitude, park.Latitude, park.Longitude)
+			if distanceKm > radiusKm {
+				continue
+			}
+			parks = append(parks, nearbyPark{
+				Reference:  park.Reference,
+				Name:       park.Name,
+				Location:   park.LocationDesc,
+				Grid:       park.Grid6,
+				Latitude:   park.Latitude,
+				Longitude:  park.Longitude,
+				Active:     park.IsActive(),
+				DistanceKm: distanceKm,
+				Bearing:    bearing,
+			})
+		}
+		sort.Slice(parks, func(i, j int) bool { return parks[i].DistanceKm < parks[j].DistanceKm })
+
+		matched := len(parks)
+		parks = parks[:min(count, matched)]
+
+		switch output {
+		case outputJSON:
+			if parks == nil {
+				parks = []nearbyPark{}
+			}
+			return jsonResult(parksNearbyResult{Origin: origin, RadiusKm: radiusKm, Matched: matched, Parks: parks})
+		case outputCSV:
+			return csvResult(parks)
+		}
+
+		if len(parks) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No POTA parks found within %s of %s", station.formatDistance(radiusKm), origin.Label)), nil
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString(fmt.Sprintf("# POTA Parks Near %s\n\n", origin.Label))
+		response.WriteString(fmt.Sprintf("**Origin:** %.4f, %.4f\n", origin.Latitude, origin.Longitude))
+		response.WriteString(fmt.Sprintf("**Radius:** %s\n\n", station.formatDistance(radiusKm)))
+		if matched > len(parks) {
+			response.WriteString(fmt.Sprintf("Showing the nearest %d of %d parks\n\n", len(parks), matched))
+		}
+
+		response.WriteString("| Reference | Name | Location | Grid | Distance | Bearing |")
+		if includeInactive {
+			response.WriteString(" Status |")
+		}
+		response.WriteString("\n|-----------|------|----------|------|----------|---------|")
+		if includeInactive {
+			response.WriteString("--------|")
+		}
+		response.WriteString("\n")
+
+		for _, park := range parks {
+			response.WriteString(fmt.Sprintf("| [%s](https://pota.app/#/park/%s) | %s | %s | %s | %s | %.0f° |",
+				park.Reference,
+				park.Reference,
+				park.Name,
+				park.Location,
+				park.Grid,
+				station.formatDistance(park.DistanceKm),
+				park.Bearing,
+			))
+			if includeInactive {
+				response.WriteString(fmt.Sprintf(" %s |", formatStatus(park.Active)))
+			}
+			response.WriteString("\n")
+		}
+
+		response.WriteString("\n\nPark list from [Parks on the Air](https://pota.app)")
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/pota"
+)
+
+// defaultSpotHistoryResults is the number of spots listed unless configured
+// or asked for
+const defaultSpotHistoryResults = 50
+
+// maxListedActivators is the number of activators named in the summary
+const maxListedActivators = 20
+
+// RegisterPotaSpotHistoryTool registers the POTA spot history tool with the MCP
+// server. limit is the number of spots listed when the call does not ask for a
+// number, or zero for the default.
+func RegisterPotaSpotHistoryTool(s Registrar, history *pota.History, station *Station, limit int) {
+	if limit <= 0 {
+		limit = defaultSpotHistoryResults
+	}
+
+	// Add tool
+	tool := mcp.NewTool("pota-spot-history",
+		mcp.WithDescription("Search past POTA spots collected in the background by activator, park, band, mode and time, e.g. who activated a park this week or when a callsign was last on a band"),
+		mcp.WithString("callsign",
+			mcp.Description("Activator callsign to filter by; a home callsign also matches its portable and mobile forms"),
+		),
+		mcp.WithString("park",
+			mcp.Description("POTA park reference to filter by (e.g., US-2312)"),
+		),
+		mcp.WithString("band",
+			mcp.Description("Band to filter by (e.g., 20m, 40m, 2m, 70cm)"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("Mode to filter by (e.g., SSB, CW, FT8)"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Start of the time window, as a time ago (e.g., 24h, 7d) or a UTC date or time (e.g., 2025-06-01, 2025-06-01T14:00). Defaults to the oldest spot kept."),
+		),
+		mcp.WithString("until",
+			mcp.Description("End of the time window, in the same forms as since (default now)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of spots to list, newest first (default %d)", limit)),
+			mcp.Min(1),
+		),
+		outputOption(true),
+	)
+
+	// Add tool handler
+	s.AddTool(tool, PotaSpotHistory(history, station, limit))
+}
+
+// potaSpotHistoryResult is the JSON output of the POTA spot history tool
+type potaSpotHistoryResult struct {
+	Since   *time.Time `json:"since,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
+	Matched int        `json:"matched"`
+	Spots   []spotRow  `json:"spots"`
+}
+
+// PotaSpotHistory returns a tool handler for searching the spot history
+func PotaSpotHistory(history *pota.History, station *Station, limit int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Get optional parameters
+		args := request.GetArguments()
+		activator, _ := args["callsign"].(string)
+		var filter spotFilter
+		filter.park, _ = args["park"].(string)
+		filter.park = strings.TrimSpace(filter.park)
+		filter.mode, _ = args["mode"].(string)
+		output, err := parseOutput(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if activator != "" {
+			if filter.activator, err = callsign.Parse(activator); err != nil {
+				return nil, err
+			}
+			activator = filter.activator.Full
+		}
+		if band, _ := args["band"].(string); band != "" {
+			filter.band = normalizeBand(station.region(), band)
+			if filter.band == "" {
+				return nil, fmt.Errorf("unknown band %q (e.g. 20m, 40m, 2m, 70cm)", band)
+			}
+		}
+
+		now := time.Now().UTC()
+		var since, until time.Time
+		if input, _ := args["since"].(string); input != "" {
+			if since, err = parseTimeBound(input, now); err != nil {
+				return nil, fmt.Errorf("invalid since: %v", err)
+			}
+		}
+		if input, _ := args["until"].(string); input != "" {
+			if until, err = parseTimeBound(input, now); err != nil {
+				return nil, fmt.Errorf("invalid until: %v", err)
+			}
+		}
+		if !since.IsZero() && !until.IsZero() && !until.After(since) {
+			return nil, errors.New("until must be after since")
+		}
+
+		count := request.GetInt("limit", limit)
+		if count < 1 {
+			return nil, errors.New("limit must be at least 1")
+		}
+
+		// The history lists spots newest first, so the first matches are the latest
+		var spots []spotRow
+		for _, spot := range history.Spots(since, until) {
+			if row := classifySpot(spot, station.region()); filter.matches(row) {
+				spots = append(spots, row)
+			}
+		}
+		matched := len(spots)
+		activators := summarizeActivators(spots)
+		if len(spots) > count {
+			spots = spots[:count]
+		}
+
+		// Structured output lists no spots rather than explaining why
+		switch output {
+		case outputJSON:
+			if spots == nil {
+				spots = []spotRow{}
+			}
+			return jsonResult(potaSpotHistoryResult{
+				Since:   optionalTime(since),
+				Until:   optionalTime(until),
+				Matched: matched,
+				Spots:   spots,
+			})
+		case outputCSV:
+			return csvResult(spots)
+		}
+
+		window := describeTimeWindow(since, until, history.Oldest())
+		if len(spots) == 0 {
+			return mcp.NewToolResultText("No POTA spots found" + describeSpotFilter(activator, filter, station) + " " + window), nil
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString("# POTA Spot History\n\n")
+		response.WriteString(fmt.Sprintf("Spots %s\n\n", window))
+
+		if activator != "" {
+			response.WriteString(fmt.Sprintf("Filtered by activator: **%s**\n\n", activator))
+		}
+		if filter.park != "" {
+			response.WriteString(fmt.Sprintf("Filtered by park: **%s**\n\n", strings.ToUpper(filter.park)))
+		}
+		if filter.band != "" {
+			response.WriteString(fmt.Sprintf("Filtered by band: **%s**\n\n", filter.band))
+		}
+		if filter.mode != "" {
+			response.WriteString(fmt.Sprintf("Filtered by mode: **%s**\n\n", filter.mode))
+		}
+
+		response.WriteString(fmt.Sprintf("**Activators:** %s\n\n", activators))
+		if matched > len(spots) {
+			response.WriteString(fmt.Sprintf("Showing the latest %d of %d spots\n\n", len(spots), matched))
+		}
+
+		response.WriteString("| Spotted At | Activator | Reference | Park Name | Frequency | Band | Mode | Location | Spotted By | Comments |\n")
+		response.WriteString("|------------|-----------|-----------|-----------|-----------|------|------|----------|------------|----------|\n")
+		for _, spot := range spots {
+			timeStr := spot.SpotTime
+			if spotTime, err := time.Parse("2006-01-02T15:04:05", spot.SpotTime); err == nil {
+				timeStr = spotTime.Format("2006-01-02 15:04 UTC")
+			}
+
+			response.WriteString(fmt.Sprintf("| %s | %s | [%s](https://pota.app/#/park/%s) | %s | %s | %s | %s | %s | %s | %s |\n",
+				timeStr,
+				spot.Activator,
+				spot.Reference,
+				spot.Reference,
+				spot.Name,
+				spot.Frequency,
+				describeSpotBand(spot),
+				spot.Mode,
+				spot.LocationDesc,
+				spot.Spotter,
+				spot.Comments,
+			))
+		}
+
+		response.WriteString("\n\nData provided by [Parks on the Air API](https://pota.app)")
+
+		return mcp.NewToolResultText(response.String()), nil
+	}
+}
+
+// parseTimeBound parses one end of a time window: a time ago as a duration,
+// which may be in days (e.g. 7d), or a UTC date or date and time
+func parseTimeBound(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if days, ok := strings.CutSuffix(input, "d"); ok {
+		if n, err := strconv.ParseFloat(days, 64); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n * 24 * float64(time.Hour))), nil
+		}
+	}
+	if d, err := time.ParseDuration(input); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.DateOnly, "2006-01-02T15:04", "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, input); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time ago (e.g. 24h, 7d) or a date (e.g. 2025-06-01)", input)
+}
+
+// describeTimeWindow describes the time window of a history search, which
+// starts at the oldest spot kept when since is not given
+func describeTimeWindow(since, until, oldest time.Time) string {
+	const layout = "2006-01-02 15:04 UTC"
+	switch {
+	case since.IsZero() && oldest.IsZero():
+		return "in the history, which is empty"
+	case since.IsZero():
+		since = oldest
+	}
+	if until.IsZero() {
+		return "since " + since.Format(layout)
+	}
+	return fmt.Sprintf("from %s to %s", since.Format(layout), until.Format(layout))
+}
+
+// summarizeActivators lists the activators of spots, given newest first,
+// with their number of spots, most recently spotted first
+func summarizeActivators(spots []spotRow) string {
+	counts := make(map[string]int)
+	var order []string
+	for _, spot := range spots {
+		if counts[spot.Activator] == 0 {
+			order = append(order, spot.Activator)
+		}
+		counts[spot.Activator]++
+	}
+
+	listed := order
+	if len(listed) > maxListedActivators {
+		listed = listed[:maxListedActivators]
+	}
+	names := make([]string, len(listed))
+	for i, activator := range listed {
+		names[i] = fmt.Sprintf("%s (%d)", activator, counts[activator])
+	}
+	summary := strings.Join(names, ", ")
+	if more := len(order) - len(listed); more > 0 {
+		summary += fmt.Sprintf(" and %d more", more)
+	}
+	return summary
+}
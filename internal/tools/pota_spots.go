@@ -3,154 +3,427 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/bandplan"
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
 	"github.com/pleska/ham-radio-assistant/internal/models"
+	"github.com/pleska/ham-radio-assistant/internal/pota"
 )
 
+// Orders accepted by the sort parameter of the POTA spots tool
 const (
-	potaSpotsAPIURL = "https://api.pota.app/spot/activator"
+	sortSpotsTime      = "time"
+	sortSpotsDistance  = "distance"
+	sortSpotsFrequency = "frequency"
 )
 
-// RegisterPotaSpotsTool registers the POTA activator spots lookup tool with the MCP server
-func RegisterPotaSpotsTool(s *server.MCPServer) {
+// RegisterPotaSpotsTool registers the POTA activator spots lookup tool with the MCP server.
+// Distances and bearings are from the station. limit is the number of spots listed when
+// the call does not ask for a number, or zero to list them all.
+func RegisterPotaSpotsTool(s Registrar, potaAPI *pota.Client, station *Station, limit int) {
 	// Add tool
 	tool := mcp.NewTool("pota-spots",
-		mcp.WithDescription("Display active POTA activations by callsign or mode"),
+		mcp.WithDescription("Display active POTA activations with band, distance and beam heading from your station, filtered by callsign, mode, band, location or distance"),
 		mcp.WithString("callsign",
-			mcp.Description("Activator callsign to filter by"),
+			mcp.Description("Activator callsign to filter by; a home callsign also matches its portable and mobile forms"),
 		),
 		mcp.WithString("mode",
 			mcp.Description("Mode to filter by (e.g., SSB, CW, FT8)"),
 		),
+		mcp.WithString("band",
+			mcp.Description("Band to filter by (e.g., 20m, 40m, 2m, 70cm)"),
+		),
+		mcp.WithString("location",
+			mcp.Description("Park location code (e.g., US-TX, VE-ON) or reference prefix (e.g., US) to filter by"),
+		),
+		mcp.WithNumber("max-distance",
+			mcp.Description("Only list parks within this distance of your station, in the station's units (miles, or km for a metric station)"),
+			mcp.Min(0),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Order of the spots: time (newest first, default), distance (nearest first) or frequency (lowest first)"),
+			mcp.Enum(sortSpotsTime, sortSpotsDistance, sortSpotsFrequency),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(limitDescription(limit)),
+			mcp.Min(1),
+		),
+		outputOption(true),
 	)
 
 	// Add tool handler
-	s.AddTool(tool, PotaSpotsLookup)
+	s.AddTool(tool, PotaSpotsLookup(potaAPI, station, limit))
 }
 
-// PotaSpotsLookup is a tool handler for looking up current POTA activations
-func PotaSpotsLookup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Get optional parameters
-	callsign, _ := request.Params.Arguments["callsign"].(string)
-	mode, _ := request.Params.Arguments["mode"].(string)
-
-	// Fetch spots from the API
-	spots, err := fetchPotaSpots(callsign, mode)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching POTA spots: %v", err)
+// limitDescription describes the limit parameter of the POTA spots tool
+func limitDescription(limit int) string {
+	if limit > 0 {
+		return fmt.Sprintf("Maximum number of spots to list (default %d)", limit)
 	}
+	return "Maximum number of spots to list (default all)"
+}
+
+// spotRow is a spot with its band and band plan segment in the station's
+// region and, when the station position is known, the short path to the park
+type spotRow struct {
+	models.POTASpot
+	Band       string   `json:"band" csv:"band"`
+	Segment    string   `json:"segment" csv:"segment"`
+	DistanceKm *float64 `json:"distanceKm,omitempty" csv:"distanceKm"`
+	Bearing    *float64 `json:"bearing,omitempty" csv:"bearing"`
+}
+
+// potaSpotsResult is the JSON output of the POTA spots tool
+type potaSpotsResult struct {
+	Matched int       `json:"matched"`
+	Spots   []spotRow `json:"spots"`
+}
 
-	// Check if any spots were found
-	if len(spots) == 0 {
-		message := "No active POTA spots found"
-		if callsign != "" {
-			message += fmt.Sprintf(" for activator %s", callsign)
+// spotFilter selects spots
+type spotFilter struct {
+	activator     *callsign.Callsign
+	park          string
+	mode          string
+	band          string
+	location      string
+	maxDistanceKm float64
+}
+
+// PotaSpotsLookup returns a tool handler for looking up current POTA activations
+func PotaSpotsLookup(potaAPI *pota.Client, station *Station, limit int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Get optional parameters
+		args := request.GetArguments()
+		activator, _ := args["callsign"].(string)
+		var filter spotFilter
+		filter.mode, _ = args["mode"].(string)
+		filter.location, _ = args["location"].(string)
+		output, err := parseOutput(request, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if activator != "" {
+			if filter.activator, err = callsign.Parse(activator); err != nil {
+				return nil, err
+			}
+			activator = filter.activator.Full
 		}
-		if mode != "" {
-			if callsign != "" {
-				message += " and"
-			} else {
-				message += " for"
+		if band, _ := args["band"].(string); band != "" {
+			filter.band = normalizeBand(station.region(), band)
+			if filter.band == "" {
+				return nil, fmt.Errorf("unknown band %q (e.g. 20m, 40m, 2m, 70cm)", band)
+			}
+		}
+		if _, ok := args["max-distance"]; ok {
+			maxDistance := request.GetFloat("max-distance", 0)
+			if maxDistance <= 0 {
+				return nil, errors.New("max-distance must be greater than zero")
+			}
+			filter.maxDistanceKm = maxDistance
+			if station == nil || !station.Metric {
+				filter.maxDistanceKm = maxDistance / kmToMiles
 			}
-			message += fmt.Sprintf(" mode %s", mode)
 		}
-		return mcp.NewToolResultText(message), nil
-	}
 
-	// Format response
-	var response strings.Builder
-	response.WriteString("# Current POTA Activations\n\n")
+		order, _ := args["sort"].(string)
+		switch order {
+		case "":
+			order = sortSpotsTime
+		case sortSpotsTime, sortSpotsDistance, sortSpotsFrequency:
+		default:
+			return nil, fmt.Errorf("unknown sort %q (expected %s, %s or %s)", order, sortSpotsTime, sortSpotsDistance, sortSpotsFrequency)
+		}
 
-	if callsign != "" {
-		response.WriteString(fmt.Sprintf("Filtered by activator: **%s**\n\n", callsign))
-	}
-	if mode != "" {
-		response.WriteString(fmt.Sprintf("Filtered by mode: **%s**\n\n", mode))
+		count := request.GetInt("limit", limit)
+		if _, ok := args["limit"]; ok && count < 1 {
+			return nil, errors.New("limit must be at least 1")
+		}
+
+		// Distances are from the station, which is optional unless the
+		// call depends on them
+		needDistance := filter.maxDistanceKm > 0 || order == sortSpotsDistance
+		var origin *place
+		if station.Configured() {
+			origin, err = station.place(ctx)
+			if err != nil && needDistance {
+				return nil, fmt.Errorf("error locating your station: %v", err)
+			}
+		}
+		if origin == nil && needDistance {
+			return nil, errors.New("max-distance and sorting by distance need a configured station")
+		}
+
+		// Fetch spots from the API
+		spots, err := fetchPotaSpots(ctx, potaAPI, filter, station.region(), origin)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching POTA spots: %v", err)
+		}
+		sortSpots(spots, order)
+		matched := len(spots)
+		if count > 0 && len(spots) > count {
+			spots = spots[:count]
+		}
+
+		// Structured output lists no spots rather than explaining why
+		switch output {
+		case outputJSON:
+			if spots == nil {
+				spots = []spotRow{}
+			}
+			return jsonResult(potaSpotsResult{Matched: matched, Spots: spots})
+		case outputCSV:
+			return csvResult(spots)
+		}
+
+		// Check if any spots were found
+		if len(spots) == 0 {
+			return mcp.NewToolResultText("No active POTA spots found" + describeSpotFilter(activator, filter, station)), nil
+		}
+
+		// Format response
+		var response strings.Builder
+		response.WriteString("# Current POTA Activations\n\n")
+
+		if activator != "" {
+			response.WriteString(fmt.Sprintf("Filtered by activator: **%s**\n\n", activator))
+		}
+		if filter.mode != "" {
+			response.WriteString(fmt.Sprintf("Filtered by mode: **%s**\n\n", filter.mode))
+		}
+		if filter.band != "" {
+			response.WriteString(fmt.Sprintf("Filtered by band: **%s**\n\n", filter.band))
+		}
+		if filter.location != "" {
+			response.WriteString(fmt.Sprintf("Filtered by location: **%s**\n\n", strings.ToUpper(filter.location)))
+		}
+		if filter.maxDistanceKm > 0 {
+			response.WriteString(fmt.Sprintf("Within **%s** of %s\n\n", station.formatDistance(filter.maxDistanceKm), origin.Label))
+		}
+		if matched > len(spots) {
+			response.WriteString(fmt.Sprintf("Showing %d of %d spots\n\n", len(spots), matched))
+		}
+
+		response.WriteString("| Activator | Reference | Park Name | Frequency | Band | Mode | Location |")
+		if origin != nil {
+			response.WriteString(" Distance | Bearing |")
+		}
+		response.WriteString(" Spotted At | Spotted By | Comments |\n")
+		response.WriteString("|-----------|-----------|-----------|-----------|------|------|----------|")
+		if origin != nil {
+			response.WriteString("----------|---------|")
+		}
+		response.WriteString("------------|------------|----------|\n")
+
+		for _, spot := range spots {
+			// Parse and format the spot time
+			spotTime, err := time.Parse("2006-01-02T15:04:05", spot.SpotTime)
+			timeStr := spot.SpotTime
+			if err == nil {
+				timeStr = spotTime.Format("15:04 UTC")
+			}
+
+			// Format row
+			response.WriteString(fmt.Sprintf("| %s | [%s](https://pota.app/#/park/%s) | %s | %s | %s | %s | %s |",
+				spot.Activator,
+				spot.Reference,
+				spot.Reference,
+				spot.Name,
+				spot.Frequency,
+				describeSpotBand(spot),
+				spot.Mode,
+				spot.LocationDesc,
+			))
+			if origin != nil {
+				distance, bearing := "", ""
+				if spot.DistanceKm != nil {
+					distance = station.formatShortDistance(*spot.DistanceKm)
+					bearing = fmt.Sprintf("%.0f°", *spot.Bearing)
+				}
+				response.WriteString(fmt.Sprintf(" %s | %s |", distance, bearing))
+			}
+			response.WriteString(fmt.Sprintf(" %s | %s | %s |\n",
+				timeStr,
+				spot.Spotter,
+				spot.Comments,
+			))
+		}
+
+		response.WriteString("\n\nData provided by [Parks on the Air API](https://pota.app)")
+
+		return mcp.NewToolResultText(response.String()), nil
 	}
+}
 
-	response.WriteString("| Activator | Reference | Park Name | Frequency | Mode | Location | Spotted At | Spotted By | Comments |\n")
-	response.WriteString("|-----------|-----------|-----------|-----------|------|----------|------------|------------|----------|\n")
+// describeSpotFilter describes the filters of a spots lookup for the
+// message shown when no spots match
+func describeSpotFilter(activator string, filter spotFilter, station *Station) string {
+	var filters []string
+	if activator != "" {
+		filters = append(filters, "activator "+activator)
+	}
+	if filter.park != "" {
+		filters = append(filters, "park "+strings.ToUpper(filter.park))
+	}
+	if filter.mode != "" {
+		filters = append(filters, "mode "+filter.mode)
+	}
+	if filter.band != "" {
+		filters = append(filters, "band "+filter.band)
+	}
+	if filter.location != "" {
+		filters = append(filters, "location "+strings.ToUpper(filter.location))
+	}
 
-	for _, spot := range spots {
-		// Parse and format the spot time
-		spotTime, err := time.Parse("2006-01-02T15:04:05", spot.SpotTime)
-		timeStr := spot.SpotTime
-		if err == nil {
-			timeStr = spotTime.Format("15:04 UTC")
-		}
-
-		// Format row
-		response.WriteString(fmt.Sprintf("| %s | [%s](https://pota.app/#/park/%s) | %s | %s | %s | %s | %s | %s | %s |\n",
-			spot.Activator,
-			spot.Reference,
-			spot.Reference,
-			spot.Name,
-			spot.Frequency,
-			spot.Mode,
-			spot.LocationDesc,
-			timeStr,
-			spot.Spotter,
-			spot.Comments,
-		))
-	}
-
-	response.WriteString("\n\nData provided by [Parks on the Air API](https://pota.app)")
-
-	return mcp.NewToolResultText(response.String()), nil
+	var description string
+	if len(filters) > 0 {
+		description = " for " + strings.Join(filters, " and ")
+	}
+	if filter.maxDistanceKm > 0 {
+		description += " within " + station.formatDistance(filter.maxDistanceKm)
+	}
+	return description
 }
 
-// fetchPotaSpots fetches current POTA activations from the API
-// and filters them based on callsign and mode if provided
-func fetchPotaSpots(callsign, mode string) ([]models.POTASpot, error) {
-	// Make the HTTP request to get all spots
-	resp, err := http.Get(potaSpotsAPIURL)
+// fetchPotaSpots fetches current POTA activations from the API, adds the
+// band and segment of each in the band plan of the region and its distance and bearing from the origin when it is
+// given, and filters them
+func fetchPotaSpots(ctx context.Context, potaAPI *pota.Client, filter spotFilter, region int, origin *place) ([]spotRow, error) {
+	spots, err := potaAPI.Spots(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error connecting to POTA API: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-OK response status: %s", resp.Status)
+	var rows []spotRow
+	for _, spot := range spots {
+		row := classifySpot(spot, region)
+		// Spots of parks without coordinates are at 0, 0
+		if origin != nil && (spot.Latitude != 0 || spot.Longitude != 0) {
+			distanceKm, _, bearing := calculateDistanceAndBearing(origin.Latitude, origin.Longitude, spot.Latitude, spot.Longitude)
+			row.DistanceKm, row.Bearing = &distanceKm, &bearing
+		}
+		if filter.matches(row) {
+			rows = append(rows, row)
+		}
 	}
 
-	// Read and parse the JSON response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
+	return rows, nil
+}
+
+// classifySpot returns a spot with its band and segment in the band plan
+// of the region
+func classifySpot(spot models.POTASpot, region int) spotRow {
+	row := spotRow{POTASpot: spot}
+	if khz, ok := parseKHz(spot.Frequency); ok {
+		if c, err := bandplan.Classify(region, khz); err == nil && c.Band != nil {
+			row.Band, row.Segment = c.Band.Name, c.Segment.Use
+		}
 	}
+	return row
+}
 
-	var spots []models.POTASpot
-	if err := json.Unmarshal(body, &spots); err != nil {
-		return nil, fmt.Errorf("error parsing JSON data: %v", err)
+// matches reports whether a spot passes every filter
+func (f spotFilter) matches(spot spotRow) bool {
+	if f.activator != nil && !matchesActivator(f.activator, spot.Activator) {
+		return false
+	}
+	if f.park != "" && !strings.EqualFold(spot.Reference, f.park) {
+		return false
+	}
+	if f.mode != "" && !strings.EqualFold(spot.Mode, f.mode) {
+		return false
+	}
+	if f.band != "" && spot.Band != f.band {
+		return false
+	}
+	if f.location != "" && !pota.InLocation(spot.Reference, spot.LocationDesc, f.location) {
+		return false
 	}
+	if f.maxDistanceKm > 0 && (spot.DistanceKm == nil || *spot.DistanceKm > f.maxDistanceKm) {
+		return false
+	}
+	return true
+}
 
-	// If no filters are provided, return all spots
-	if callsign == "" && mode == "" {
-		return spots, nil
+// sortSpots orders spots by time (newest first), distance (nearest first,
+// then spots without a position) or frequency (lowest first)
+func sortSpots(spots []spotRow, order string) {
+	switch order {
+	case sortSpotsTime:
+		// Spot times share one format, so they sort as text
+		sort.SliceStable(spots, func(i, j int) bool { return spots[i].SpotTime > spots[j].SpotTime })
+	case sortSpotsDistance:
+		sort.SliceStable(spots, func(i, j int) bool {
+			a, b := spots[i].DistanceKm, spots[j].DistanceKm
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return *a < *b
+		})
+	case sortSpotsFrequency:
+		sort.SliceStable(spots, func(i, j int) bool {
+			a, okA := parseKHz(spots[i].Frequency)
+			b, okB := parseKHz(spots[j].Frequency)
+			if !okA || !okB {
+				return okA
+			}
+			return a < b
+		})
 	}
+}
 
-	// Filter spots based on callsign and/or mode
-	var filteredSpots []models.POTASpot
-	for _, spot := range spots {
-		// Check if spot matches the callsign filter
-		callsignMatch := callsign == "" || strings.EqualFold(spot.Activator, callsign)
+// parseKHz parses a frequency in kHz as spotted (e.g. 14074 or 7032.5)
+func parseKHz(frequency string) (float64, bool) {
+	khz, err := strconv.ParseFloat(strings.TrimSpace(frequency), 64)
+	return khz, err == nil && khz > 0
+}
 
-		// Check if spot matches the mode filter
-		modeMatch := mode == "" || strings.EqualFold(spot.Mode, mode)
+// normalizeBand returns the name of a band in the band plan of the region,
+// or DefaultRegion when region is zero, accepting meter bands without the
+// unit (e.g. 20 for 20m). It returns an empty string when there is no such
+// band.
+func normalizeBand(region int, name string) string {
+	if region == 0 {
+		region = bandplan.DefaultRegion
+	}
+	plan, err := bandplan.ForRegion(region)
+	if err != nil {
+		return ""
+	}
+	if band, ok := plan.BandByName(name); ok {
+		return band.Name
+	}
+	return ""
+}
 
-		// Add to filtered results if both conditions are met
-		if callsignMatch && modeMatch {
-			filteredSpots = append(filteredSpots, spot)
-		}
+// describeSpotBand formats the band of a spot with its band plan segment
+// (e.g. 20m CW)
+func describeSpotBand(spot spotRow) string {
+	if spot.Segment == "" {
+		return spot.Band
 	}
+	return spot.Band + " " + spot.Segment
+}
 
-	return filteredSpots, nil
+// matchesActivator reports whether a spotted activator matches the callsign
+// filter. A filter without a prefix or suffix matches every form of the same
+// home call (W1AW matches W1AW/P and VE3/W1AW); otherwise the full callsign
+// must match.
+func matchesActivator(filter *callsign.Callsign, activator string) bool {
+	spotted, err := callsign.Parse(activator)
+	if err != nil {
+		return strings.EqualFold(filter.Full, activator)
+	}
+	if filter.Full == filter.Base {
+		return spotted.Base == filter.Base
+	}
+	return spotted.Full == filter.Full
 }
@@ -0,0 +1,12 @@
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Registrar is what tools are registered with. *server.MCPServer is one;
+// wrappers can leave tools out or change them before they are added.
+type Registrar interface {
+	AddTool(tool mcp.Tool, handler server.ToolHandlerFunc)
+}
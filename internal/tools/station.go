@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/callsign"
+	"github.com/pleska/ham-radio-assistant/internal/config"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+)
+
+// Units accepted in the station profile
+const (
+	unitsImperial = "imperial"
+	unitsMetric   = "metric"
+)
+
+// errNoStation is returned when a tool needs the station but none is configured
+var errNoStation = errors.New("no station is configured")
+
+// Station is your own station from the configuration. Tools use it as the
+// origin when none is given and report results relative to it.
+type Station struct {
+	// Callsign is nil when no callsign is configured
+	Callsign *callsign.Callsign
+	Grid     string
+	// Latitude and Longitude are set when HasLocation is; otherwise the
+	// position is looked up from the callsign when needed
+	Latitude     float64
+	Longitude    float64
+	HasLocation  bool
+	LicenseClass string
+	ITURegion    int
+	Metric       bool
+	// TimeZone is nil when no time zone is configured
+	TimeZone *time.Location
+
+	provider lookup.CallsignProvider
+	entities *dxcc.Database
+}
+
+// NewStation builds the station profile from the configuration. The
+// provider and entities are used to locate the station from its callsign
+// when no position is configured; entities may be nil.
+func NewStation(cfg config.StationConfig, provider lookup.CallsignProvider, entities *dxcc.Database) (*Station, error) {
+	station := &Station{
+		LicenseClass: cfg.LicenseClass,
+		ITURegion:    cfg.ITURegion,
+		provider:     provider,
+		entities:     entities,
+	}
+
+	if cfg.Callsign != "" {
+		call, err := callsign.Parse(cfg.Callsign)
+		if err != nil {
+			return nil, fmt.Errorf("invalid station callsign: %w", err)
+		}
+		station.Callsign = call
+	}
+
+	if cfg.Grid != "" {
+		square, err := maidenhead.Parse(cfg.Grid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid station grid: %v", err)
+		}
+		station.Grid = square.Locator
+		station.Latitude, station.Longitude = square.Center()
+		station.HasLocation = true
+	}
+
+	// Coordinates are more precise than a grid square, so they take
+	// precedence for the position
+	if (cfg.Latitude == nil) != (cfg.Longitude == nil) {
+		return nil, errors.New("station latitude and longitude must be given together")
+	}
+	if cfg.Latitude != nil {
+		grid, err := maidenhead.Encode(*cfg.Latitude, *cfg.Longitude, 6)
+		if err != nil {
+			return nil, fmt.Errorf("invalid station coordinates: %v", err)
+		}
+		if station.Grid == "" {
+			station.Grid = grid
+		}
+		station.Latitude, station.Longitude = *cfg.Latitude, *cfg.Longitude
+		station.HasLocation = true
+	}
+
+	if cfg.ITURegion < 0 || cfg.ITURegion > 3 {
+		return nil, fmt.Errorf("invalid station ITU region %d (expected 1, 2 or 3)", cfg.ITURegion)
+	}
+
+	switch cfg.Units {
+	case "", unitsImperial:
+	case unitsMetric:
+		station.Metric = true
+	default:
+		return nil, fmt.Errorf("unknown station units %q (expected %s or %s)", cfg.Units, unitsImperial, unitsMetric)
+	}
+
+	if cfg.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid station time zone: %v", err)
+		}
+		station.TimeZone = location
+	}
+
+	return station, nil
+}
+
+// Configured reports whether the station can be located
+func (s *Station) Configured() bool {
+	return s != nil && (s.HasLocation || s.Callsign != nil)
+}
+
+// Name describes the station in tool results
+func (s *Station) Name() string {
+	switch {
+	case s.Callsign != nil:
+		return fmt.Sprintf("Your station (%s)", s.Callsign)
+	case s.Grid != "":
+		return fmt.Sprintf("Your station (%s)", s.Grid)
+	}
+	return "Your station"
+}
+
+// locate returns the station position, looking it up from the callsign when
+// no position is configured
+func (s *Station) locate(ctx context.Context) (*callsignLocation, error) {
+	if !s.Configured() {
+		return nil, errNoStation
+	}
+	if s.HasLocation {
+		return &callsignLocation{Latitude: s.Latitude, Longitude: s.Longitude, Gridsquare: s.Grid}, nil
+	}
+
+	location, err := locateCallsign(ctx, s.provider, s.entities, s.Callsign)
+	if err != nil {
+		return nil, fmt.Errorf("error locating your station %s: %w", s.Callsign, err)
+	}
+	return location, nil
+}
+
+// place returns the station as a labelled place
+func (s *Station) place(ctx context.Context) (*place, error) {
+	location, err := s.locate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &place{Label: s.Name(), Latitude: location.Latitude, Longitude: location.Longitude}, nil
+}
+
+// endpoint returns the station as one end of a path
+func (s *Station) endpoint(ctx context.Context) (*endpoint, error) {
+	location, err := s.locate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := location.Gridsquare
+	if grid == "" {
+		if grid, err = maidenhead.Encode(location.Latitude, location.Longitude, 6); err != nil {
+			return nil, err
+		}
+	}
+	return &endpoint{Label: s.Name(), Latitude: location.Latitude, Longitude: location.Longitude, Grid: grid}, nil
+}
+
+// region returns the station's IARU region for band plans, or zero when it
+// is not configured
+func (s *Station) region() int {
+	if s == nil {
+		return 0
+	}
+	return s.ITURegion
+}
+
+// formatDistance formats a distance in the station's preferred units, with
+// the other units in parentheses. A nil station uses imperial units.
+func (s *Station) formatDistance(km float64) string {
+	if s != nil && s.Metric {
+		return fmt.Sprintf("%.2f km (%.2f miles)", km, km*kmToMiles)
+	}
+	return fmt.Sprintf("%.2f miles (%.2f km)", km*kmToMiles, km)
+}
+
+// formatShortDistance formats a rounded distance in the station's preferred
+// units alone, for tables. A nil station uses imperial units.
+func (s *Station) formatShortDistance(km float64) string {
+	if s != nil && s.Metric {
+		return fmt.Sprintf("%.0f km", km)
+	}
+	return fmt.Sprintf("%.0f mi", km*kmToMiles)
+}
+
+// formatTime formats a time in UTC, followed by the station's local time
+// when a time zone is configured
+func (s *Station) formatTime(t time.Time) string {
+	result := t.UTC().Format("2006-01-02 15:04 UTC")
+	if s != nil && s.TimeZone != nil {
+		result += " (" + t.In(s.TimeZone).Format("15:04 MST") + ")"
+	}
+	return result
+}
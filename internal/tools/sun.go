@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pleska/ham-radio-assistant/internal/dxcc"
+	"github.com/pleska/ham-radio-assistant/internal/lookup"
+	"github.com/pleska/ham-radio-assistant/internal/solar"
+)
+
+// grayLineSpan is how far ahead gray-line overlap windows are searched
+const grayLineSpan = 24 * time.Hour
+
+// RegisterSunTimesTool registers the sun times tool with the MCP server.
+// entities may be nil, in which case callsigns without coordinates cannot be located.
+// The location defaults to the station.
+func RegisterSunTimesTool(s Registrar, provider lookup.CallsignProvider, entities *dxcc.Database, station *Station) {
+	options := []mcp.ToolOption{
+		mcp.WithDescription("Calculate sunrise, sunset, solar noon and civil twilight for a location given as coordinates, a grid square or a callsign. The location defaults to your station."),
+	}
+	options = append(options, placeOptions("location", "Location")...)
+	options = append(options,
+		mcp.WithString("date",
+			mcp.Description("Date as YYYY-MM-DD (default today, UTC)"),
+		),
+		outputOption(false),
+	)
+	tool := mcp.NewTool("sun-times", options...)
+
+	// Add tool handler
+	s.AddTool(tool, SunTimes(provider, entities, station))
+}
+
+// SunTimes returns a tool handler for calculating sun times
+func SunTimes(provider lookup.CallsignProvider, entities *dxcc.Database, station *Station) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		output, err := parseOutput(request, false)
+		if err != nil {
+			return nil, err
+		}
+
+		date := time.Now().UTC()
+		if input, _ := request.GetArguments()["date"].(string); input != "" {
+			if date, err = time.Parse(time.DateOnly, input); err != nil {
+				return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", input)
+			}
+		}
+
+		location, err := resolveOrigin(ctx, request, "location", station, provider, entities)
+		if errors.Is(err, lookup.ErrNotFound) {
+			return mcp.NewToolResultText(fmt.Sprintf("Callsign %s is not valid", request.GetArguments()["location-callsign"])), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		day := solar.Times(date, location.Latitude, location.Longitude)
+
+		if output == outputJSON {
+			return jsonResult(sunTimesResult{
+				Location:         location,
+				Date:             day.Date.Format(time.DateOnly),
+				CivilDawn:        optionalTime(day.CivilDawn),
+				Sunrise:          optionalTime(day.Sunrise),
+				SolarNoon:        day.SolarNoon.Round(time.Second),
+				Sunset:           optionalTime(day.Sunset),
+				CivilDusk:        optionalTime(day.CivilDusk),
+				AlwaysUp:         day.AlwaysUp,
+				AlwaysDown:       day.AlwaysDown,
+				DayLengthMinutes: int(day.DayLength().Minutes()),
+			})
+		}
+
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("## Sun Times for %s\n\n", location.Label))
+		result.WriteString(fmt.Sprintf("**Location:** %.4f, %.4f\n", location.Latitude, location.Longitude))
+		result.WriteString(fmt.Sprintf("**Date:** %s\n\n", day.Date.Format(time.DateOnly)))
+		result.WriteString(fmt.Sprintf("**Civil Dawn:** %s\n", formatSunEvent(day.CivilDawn, day, station)))
+		result.WriteString(fmt.Sprintf("**Sunrise:** %s\n", formatSunEvent(day.Sunrise, day, station)))
+		result.WriteString(fmt.Sprintf("**Solar Noon:** %s\n", station.formatTime(day.SolarNoon)))
+		result.WriteString(fmt.Sprintf("**Sunset:** %s\n", formatSunEvent(day.Sunset, day, station)))
+		result.WriteString(fmt.Sprintf("**Civil Dusk:** %s\n\n", formatSunEvent(day.CivilDusk, day, station)))
+
+		length := day.DayLength()
+		result.WriteString(fmt.Sprintf("**Day Length:** %dh %02dm\n\n", int(length.Hours()), int(length.Minutes())%60))
+		result.WriteString("Times are for the solar day whose noon falls on the given UTC date")
+
+		return mcp.NewToolResultText(result.String()), nil
+	}
+}
+
+// sunTimesResult is the JSON output of the sun times tool. Events that do
+// not happen on the day are omitted.
+type sunTimesResult struct {
+	Location         *place     `json:"location"`
+	Date             string     `json:"date"`
+	CivilDawn        *time.Time `json:"civilDawn,omitempty"`
+	Sunrise          *time.Time `json:"sunrise,omitempty"`
+	SolarNoon        time.Time  `json:"solarNoon"`
+	Sunset           *time.Time `json:"sunset,omitempty"`
+	CivilDusk        *time.Time `json:"civilDusk,omitempty"`
+	AlwaysUp         bool       `json:"alwaysUp"`
+	AlwaysDown       bool       `json:"alwaysDown"`
+	DayLengthMinutes int        `json:"dayLengthMinutes"`
+}
+
+// optionalTime returns the time to the second, or nil for the zero time
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.Round(time.Second)
+	return &t
+}
+
+// formatSunEvent formats a sun event, explaining why it is missing during
+// the midnight sun or polar night
+func formatSunEvent(t time.Time, day *solar.Day, station *Station) string {
+	switch {
+	case !t.IsZero():
+		return station.formatTime(t)
+	case day.AlwaysUp:
+		return "none (the sun does not set)"
+	case day.AlwaysDown:
+		return "none (the sun does not rise)"
+	default:
+		return "none (twilight lasts all night)"
+	}
+}
+
+// grayLineWindows returns the gray-line overlap windows between two places
+// over the next day
+func grayLineWindows(from, to *callsignLocation) []solar.Window {
+	now := time.Now().UTC().Truncate(time.Minute)
+	return solar.GrayLineOverlap(now, grayLineSpan, from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+}
+
+// formatGrayLine formats gray-line overlap windows between two places
+func formatGrayLine(fromLabel, toLabel string, windows []solar.Window, station *Station) string {
+	result := "### Gray Line (next 24 hours)\n\n"
+	if len(windows) == 0 {
+		return result + "No gray-line overlap between the two stations in the next 24 hours"
+	}
+	for _, w := range windows {
+		result += fmt.Sprintf("- %s for %d min: %s at %s, %s at %s\n",
+			station.formatTime(w.Start), int(w.End.Sub(w.Start).Minutes()),
+			twilightName(w.FromRising), fromLabel, twilightName(w.ToRising), toLabel)
+	}
+	return result + "\nBoth stations are between civil twilight and sunrise or sunset"
+}
+
+// twilightName names the twilight for a rising or setting sun
+func twilightName(rising bool) string {
+	if rising {
+		return "dawn"
+	}
+	return "dusk"
+}
@@ -0,0 +1,247 @@
+package uls
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pleska/ham-radio-assistant/internal/maidenhead"
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// ImportStats summarizes an import run
+type ImportStats struct {
+	Licenses  int
+	Geocoded  int
+	Skipped   int
+	StorePath string
+}
+
+// operatorClasses maps ULS operator class codes to the names used by callook.info
+var operatorClasses = map[string]string{
+	"E": "EXTRA",
+	"A": "ADVANCED",
+	"G": "GENERAL",
+	"P": "TECHNICIAN PLUS",
+	"T": "TECHNICIAN",
+	"N": "NOVICE",
+}
+
+// licenseTypes maps ULS applicant type codes to the names used by callook.info
+var licenseTypes = map[string]string{
+	"B": "CLUB",
+	"M": "MILITARY",
+	"R": "RACES",
+}
+
+// ulsLicenseURL links to the public ULS license page for a system identifier
+const ulsLicenseURL = "https://wireless2.fcc.gov/UlsApp/UlsSearch/license.jsp?licKey=%s"
+
+// Import reads the weekly FCC ULS amateur license archive (l_amat.zip) and
+// writes the active licenses to a store at storePath. The optional
+// zipCentroidsPath names a US Census ZCTA gazetteer file used to give each
+// license an approximate location at the centroid of its ZIP code, since the
+// ULS itself carries no coordinates.
+func Import(archivePath, storePath, zipCentroidsPath string) (*ImportStats, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ULS archive: %w", err)
+	}
+	defer archive.Close()
+
+	var centroids map[string][2]float64
+	if zipCentroidsPath != "" {
+		if centroids, err = loadZipCentroids(zipCentroidsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := &ImportStats{StorePath: storePath}
+	licenses := make(map[string]*models.CallsignResponse)
+
+	// HD holds the license header; only active licenses are kept
+	err = readDatFile(&archive.Reader, "HD.dat", func(fields []string) {
+		if len(fields) < 44 || fields[5] != "A" {
+			stats.Skipped++
+			return
+		}
+		record := &models.CallsignResponse{Status: "VALID", Type: "PERSON"}
+		record.Current.Callsign = strings.ToUpper(fields[4])
+		record.OtherInfo.GrantDate = fields[7]
+		record.OtherInfo.ExpiryDate = fields[8]
+		record.OtherInfo.LastActionDate = fields[43]
+		record.OtherInfo.UlsUrl = fmt.Sprintf(ulsLicenseURL, fields[1])
+		licenses[fields[1]] = record
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// EN holds the licensee name, address and FRN
+	err = readDatFile(&archive.Reader, "EN.dat", func(fields []string) {
+		if len(fields) < 24 || fields[5] != "L" {
+			return
+		}
+		record, ok := licenses[fields[1]]
+		if !ok {
+			return
+		}
+		record.Name = joinNonEmpty(" ", fields[8], fields[9], fields[10], fields[11])
+		if record.Name == "" {
+			record.Name = fields[7]
+		}
+		record.Address.Line1 = fields[15]
+		if record.Address.Line1 == "" && fields[19] != "" {
+			record.Address.Line1 = "PO BOX " + fields[19]
+		}
+		record.Address.Line2 = joinNonEmpty(" ", joinNonEmpty(", ", fields[16], fields[17]), fields[18])
+		record.Address.Attn = fields[20]
+		record.OtherInfo.Frn = fields[22]
+		if licenseType, ok := licenseTypes[fields[23]]; ok {
+			record.Type = licenseType
+		}
+
+		if zip := fields[18]; len(zip) >= 5 {
+			if centroid, ok := centroids[zip[:5]]; ok {
+				record.Location.Latitude = strconv.FormatFloat(centroid[0], 'f', 6, 64)
+				record.Location.Longitude = strconv.FormatFloat(centroid[1], 'f', 6, 64)
+				record.Location.Gridsquare, _ = maidenhead.Encode(centroid[0], centroid[1], 6)
+				stats.Geocoded++
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// AM holds the operator class, trustee and previous callsign
+	err = readDatFile(&archive.Reader, "AM.dat", func(fields []string) {
+		if len(fields) < 18 {
+			return
+		}
+		record, ok := licenses[fields[1]]
+		if !ok {
+			return
+		}
+		record.Current.OperClass = operatorClasses[fields[5]]
+		record.Trustee.Callsign = fields[8]
+		record.Trustee.Name = fields[17]
+		record.Previous.Callsign = fields[15]
+		record.Previous.OperClass = operatorClasses[fields[16]]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Collapse to one record per callsign
+	byCallsign := make(map[string]*models.CallsignResponse, len(licenses))
+	for _, record := range licenses {
+		byCallsign[record.Current.Callsign] = record
+	}
+	records := make([]*models.CallsignResponse, 0, len(byCallsign))
+	for _, record := range byCallsign {
+		records = append(records, record)
+	}
+	stats.Licenses = len(records)
+
+	if err := writeStore(storePath, records, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// readDatFile calls fn with the fields of each pipe-delimited record in the
+// named file of the archive
+func readDatFile(archive *zip.Reader, name string, fn func(fields []string)) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("failed to open %s in ULS archive: %w", name, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 1<<20)
+	for {
+		line, err := reader.ReadString('\n')
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			fn(strings.Split(line, "|"))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+	}
+}
+
+// loadZipCentroids reads a US Census ZCTA gazetteer file (tab-separated with
+// GEOID, INTPTLAT and INTPTLONG columns) into a map of ZIP code to lat/lon
+func loadZipCentroids(path string) (map[string][2]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ZIP centroid file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = '\t'
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading ZIP centroid header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	geoidCol, okID := columns["GEOID"]
+	latCol, okLat := columns["INTPTLAT"]
+	lonCol, okLon := columns["INTPTLONG"]
+	if !okID || !okLat || !okLon {
+		return nil, fmt.Errorf("ZIP centroid file must have GEOID, INTPTLAT and INTPTLONG columns")
+	}
+
+	centroids := make(map[string][2]float64)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading ZIP centroid file: %w", err)
+		}
+		if len(row) <= lonCol || len(row) <= latCol || len(row) <= geoidCol {
+			continue
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(row[latCol]), 64)
+		if err != nil {
+			continue
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(row[lonCol]), 64)
+		if err != nil {
+			continue
+		}
+		centroids[strings.TrimSpace(row[geoidCol])] = [2]float64{lat, lon}
+	}
+
+	return centroids, nil
+}
+
+// joinNonEmpty joins the non-empty parts with the separator
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
@@ -0,0 +1,219 @@
+// Package uls imports the FCC Universal Licensing System (ULS) amateur
+// license database into a compact local store and answers callsign
+// lookups from it without network access
+package uls
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pleska/ham-radio-assistant/internal/models"
+)
+
+// ErrNotFound is returned when the store has no license for a callsign
+var ErrNotFound = errors.New("callsign not found")
+
+// storeMagic identifies a ULS store file and its format version
+const storeMagic = "HAMULS1\n"
+
+// blockSize is the number of records between sparse index entries
+const blockSize = 64
+
+// indexEntry points at the first record of a block of records
+type indexEntry struct {
+	Callsign string
+	Offset   int64
+}
+
+// Store is a read-only callsign store backed by a file of records sorted by
+// callsign with a sparse index, so lookups only read a single small block
+type Store struct {
+	file     *os.File
+	index    []indexEntry
+	dataEnd  int64
+	count    int
+	Imported string
+}
+
+// storeTrailer holds the store metadata written after the index
+type storeTrailer struct {
+	Count    int
+	Imported string
+}
+
+// Open opens a store file written by Import
+func Open(path string) (*Store, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ULS store: %w", err)
+	}
+
+	store, err := readStore(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read ULS store %s: %w", path, err)
+	}
+
+	return store, nil
+}
+
+// readStore reads the header, index and trailer of a store file
+func readStore(file *os.File) (*Store, error) {
+	magic := make([]byte, len(storeMagic))
+	if _, err := io.ReadFull(file, magic); err != nil || string(magic) != storeMagic {
+		return nil, errors.New("not a ULS store file")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// The last 8 bytes hold the offset of the gob-encoded index and trailer
+	var footer [8]byte
+	if _, err := file.ReadAt(footer[:], info.Size()-8); err != nil {
+		return nil, err
+	}
+	indexOffset := int64(binary.BigEndian.Uint64(footer[:]))
+	if indexOffset < int64(len(storeMagic)) || indexOffset > info.Size()-8 {
+		return nil, errors.New("corrupt index offset")
+	}
+
+	decoder := gob.NewDecoder(io.NewSectionReader(file, indexOffset, info.Size()-8-indexOffset))
+	store := &Store{file: file, dataEnd: indexOffset}
+	if err := decoder.Decode(&store.index); err != nil {
+		return nil, fmt.Errorf("error decoding index: %w", err)
+	}
+	var trailer storeTrailer
+	if err := decoder.Decode(&trailer); err != nil {
+		return nil, fmt.Errorf("error decoding trailer: %w", err)
+	}
+	store.count = trailer.Count
+	store.Imported = trailer.Imported
+
+	return store, nil
+}
+
+// Len returns the number of licenses in the store
+func (s *Store) Len() int {
+	return s.count
+}
+
+// Close closes the underlying file. Closing a nil store does nothing.
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.file.Close()
+}
+
+// Lookup returns the license record for a callsign in the same shape as a
+// callook.info response, or ErrNotFound
+func (s *Store) Lookup(callsign string) (*models.CallsignResponse, error) {
+	callsign = strings.ToUpper(strings.TrimSpace(callsign))
+
+	// Find the last block whose first callsign is not after the target
+	i := sort.Search(len(s.index), func(i int) bool {
+		return s.index[i].Callsign > callsign
+	}) - 1
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	end := s.dataEnd
+	if i+1 < len(s.index) {
+		end = s.index[i+1].Offset
+	}
+
+	prefix := []byte(callsign + "\t")
+	scanner := bufio.NewScanner(io.NewSectionReader(s.file, s.index[i].Offset, end-s.index[i].Offset))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !bytes.HasPrefix(line, prefix) {
+			continue
+		}
+
+		var record models.CallsignResponse
+		if err := json.Unmarshal(line[len(prefix):], &record); err != nil {
+			return nil, fmt.Errorf("error decoding record for %s: %w", callsign, err)
+		}
+		return &record, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ULS store: %w", err)
+	}
+
+	return nil, ErrNotFound
+}
+
+// writeStore writes the records, sorted by callsign, to a new store file
+func writeStore(path string, records []*models.CallsignResponse, imported string) error {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Current.Callsign < records[j].Current.Callsign
+	})
+
+	// Write to a temporary file first so an interrupted import never
+	// replaces a good store with a partial one
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create ULS store: %w", err)
+	}
+	defer os.Remove(tmpPath)
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	offset := int64(len(storeMagic))
+	if _, err := w.WriteString(storeMagic); err != nil {
+		return err
+	}
+
+	var index []indexEntry
+	for i, record := range records {
+		if i%blockSize == 0 {
+			index = append(index, indexEntry{Callsign: record.Current.Callsign, Offset: offset})
+		}
+
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("error encoding record for %s: %w", record.Current.Callsign, err)
+		}
+		line := record.Current.Callsign + "\t" + string(data) + "\n"
+		if _, err := w.WriteString(line); err != nil {
+			return err
+		}
+		offset += int64(len(line))
+	}
+
+	encoder := gob.NewEncoder(w)
+	if err := encoder.Encode(index); err != nil {
+		return fmt.Errorf("error encoding index: %w", err)
+	}
+	if err := encoder.Encode(storeTrailer{Count: len(records), Imported: imported}); err != nil {
+		return fmt.Errorf("error encoding trailer: %w", err)
+	}
+
+	var footer [8]byte
+	binary.BigEndian.PutUint64(footer[:], uint64(offset))
+	if _, err := w.Write(footer[:]); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
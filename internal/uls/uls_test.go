@@ -0,0 +1,187 @@
+package uls
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// datLine builds a pipe-delimited ULS record of n fields from the given
+// field values by index
+func datLine(n int, fields map[int]string) string {
+	line := make([]string, n)
+	for i, value := range fields {
+		line[i] = value
+	}
+	return strings.Join(line, "|")
+}
+
+// writeArchive writes a ULS archive holding the named .dat files
+func writeArchive(t *testing.T, files map[string][]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "l_amat.zip")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for name, lines := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(strings.Join(lines, "\r\n") + "\r\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportAndLookup(t *testing.T) {
+	hd := []string{
+		datLine(51, map[int]string{1: "100", 4: "w1aw", 5: "A", 7: "01/01/2020", 8: "01/01/2030", 43: "06/01/2024"}),
+		datLine(51, map[int]string{1: "101", 4: "K1ABC", 5: "A"}),
+		// Expired licenses are skipped
+		datLine(51, map[int]string{1: "102", 4: "N0OLD", 5: "E"}),
+	}
+	en := []string{
+		datLine(30, map[int]string{1: "100", 5: "L", 7: "ARRL INC", 15: "225 MAIN ST", 16: "NEWINGTON", 17: "CT", 18: "061111400", 22: "0001234567", 23: "B"}),
+		datLine(30, map[int]string{1: "101", 5: "L", 8: "Jane", 9: "Q", 10: "Public", 16: "BOSTON", 17: "MA", 18: "02101", 19: "42"}),
+	}
+	am := []string{
+		datLine(18, map[int]string{1: "100", 8: "K1ZZ", 17: "Trustee Name"}),
+		datLine(18, map[int]string{1: "101", 5: "E", 15: "KB1XYZ", 16: "G"}),
+	}
+	archivePath := writeArchive(t, map[string][]string{"HD.dat": hd, "EN.dat": en, "AM.dat": am})
+
+	centroidsPath := filepath.Join(t.TempDir(), "zcta.txt")
+	centroids := "GEOID\tALAND\tINTPTLAT\tINTPTLONG\n06111\t0\t41.686\t-72.729\n"
+	if err := os.WriteFile(centroidsPath, []byte(centroids), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	storePath := filepath.Join(t.TempDir(), "uls.store")
+	stats, err := Import(archivePath, storePath, centroidsPath)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if stats.Licenses != 2 || stats.Skipped != 1 || stats.Geocoded != 1 {
+		t.Errorf("stats = %+v, want 2 licenses, 1 skipped, 1 geocoded", stats)
+	}
+
+	store, err := Open(storePath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+	if store.Len() != 2 {
+		t.Errorf("Len = %d, want 2", store.Len())
+	}
+
+	w1aw, err := store.Lookup(" w1aw ")
+	if err != nil {
+		t.Fatalf("Lookup W1AW: %v", err)
+	}
+	checks := []struct {
+		field, got, want string
+	}{
+		{"callsign", w1aw.Current.Callsign, "W1AW"},
+		{"type", w1aw.Type, "CLUB"},
+		{"name", w1aw.Name, "ARRL INC"},
+		{"address", w1aw.Address.Line2, "NEWINGTON, CT 061111400"},
+		{"trustee", w1aw.Trustee.Callsign, "K1ZZ"},
+		{"grid", w1aw.Location.Gridsquare, "FN31pq"},
+		{"uls url", w1aw.OtherInfo.UlsUrl, fmt.Sprintf(ulsLicenseURL, "100")},
+	}
+	k1abc, err := store.Lookup("K1ABC")
+	if err != nil {
+		t.Fatalf("Lookup K1ABC: %v", err)
+	}
+	checks = append(checks, []struct {
+		field, got, want string
+	}{
+		{"name", k1abc.Name, "Jane Q Public"},
+		{"type", k1abc.Type, "PERSON"},
+		{"class", k1abc.Current.OperClass, "EXTRA"},
+		{"previous class", k1abc.Previous.OperClass, "GENERAL"},
+		{"po box", k1abc.Address.Line1, "PO BOX 42"},
+		{"no centroid", k1abc.Location.Gridsquare, ""},
+	}...)
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %q, want %q", c.field, c.got, c.want)
+		}
+	}
+
+	if _, err := store.Lookup("N0OLD"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup N0OLD error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookupAcrossBlocks(t *testing.T) {
+	var hd []string
+	for i := range 3 * blockSize {
+		hd = append(hd, datLine(44, map[int]string{1: fmt.Sprint(i), 4: fmt.Sprintf("K%04d", i), 5: "A"}))
+	}
+	archivePath := writeArchive(t, map[string][]string{"HD.dat": hd, "EN.dat": nil, "AM.dat": nil})
+	storePath := filepath.Join(t.TempDir(), "uls.store")
+	if _, err := Import(archivePath, storePath, ""); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	store, err := Open(storePath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+
+	tests := []struct {
+		callsign string
+		found    bool
+	}{
+		{"K0000", true},
+		{"K0063", true},
+		{"K0064", true},
+		{"K0191", true},
+		{"A0000", false},
+		{"K0192", false},
+		{"K00635", false},
+	}
+	for _, tt := range tests {
+		record, err := store.Lookup(tt.callsign)
+		switch {
+		case tt.found && err != nil:
+			t.Errorf("Lookup(%s): %v", tt.callsign, err)
+		case tt.found && record.Current.Callsign != tt.callsign:
+			t.Errorf("Lookup(%s) = %s", tt.callsign, record.Current.Callsign)
+		case !tt.found && !errors.Is(err, ErrNotFound):
+			t.Errorf("Lookup(%s) error = %v, want ErrNotFound", tt.callsign, err)
+		}
+	}
+}
+
+func TestJoinNonEmpty(t *testing.T) {
+	tests := []struct {
+		sep   string
+		parts []string
+		want  string
+	}{
+		{" ", []string{"Jane", "", "Public"}, "Jane Public"},
+		{", ", []string{" BOSTON ", "MA"}, "BOSTON, MA"},
+		{" ", []string{"", " "}, ""},
+		{" ", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := joinNonEmpty(tt.sep, tt.parts...); got != tt.want {
+			t.Errorf("joinNonEmpty(%q, %q) = %q, want %q", tt.sep, tt.parts, got, tt.want)
+		}
+	}
+}
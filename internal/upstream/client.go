@@ -0,0 +1,249 @@
+// Package upstream provides the HTTP client shared by every call to an
+// upstream service (callsign databases and the POTA API). It carries the
+// caller's context, bounds each attempt with a per-host timeout, retries
+// idempotent requests that time out or fail with a server error, and
+// identifies the application with a User-Agent header.
+package upstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// UserAgent identifies this application to upstream services
+const UserAgent = "ham-radio-assistant/1.1 (+https://github.com/pleska/ham-radio-assistant)"
+
+// Defaults applied to zero Options fields
+const (
+	DefaultTimeout = 10 * time.Second
+	DefaultRetries = 2
+	DefaultBackoff = 250 * time.Millisecond
+)
+
+// maxBackoff caps the delay between retries
+const maxBackoff = 5 * time.Second
+
+// Options configures a Client
+type Options struct {
+	// Timeout bounds each attempt, including reading the response body
+	Timeout time.Duration
+	// HostTimeouts overrides Timeout for individual hosts
+	HostTimeouts map[string]time.Duration
+	// Retries is the number of times a failed idempotent request is
+	// retried. Use a negative value to disable retries.
+	Retries int
+	// Backoff is the delay before the first retry, doubled for each
+	// further retry
+	Backoff time.Duration
+	// Observe, when set, is called after every request attempt
+	Observe func(Request)
+}
+
+// Client sends requests to upstream services
+type Client struct {
+	http         *http.Client
+	timeout      time.Duration
+	hostTimeouts map[string]time.Duration
+	retries      int
+	backoff      time.Duration
+	observe      func(Request)
+}
+
+// New creates a client with the given options
+func New(opts Options) *Client {
+	c := &Client{
+		http:         &http.Client{},
+		timeout:      opts.Timeout,
+		hostTimeouts: opts.HostTimeouts,
+		retries:      opts.Retries,
+		backoff:      opts.Backoff,
+		observe:      opts.Observe,
+	}
+	if c.timeout <= 0 {
+		c.timeout = DefaultTimeout
+	}
+	if c.retries == 0 {
+		c.retries = DefaultRetries
+	} else if c.retries < 0 {
+		c.retries = 0
+	}
+	if c.backoff <= 0 {
+		c.backoff = DefaultBackoff
+	}
+	return c
+}
+
+// Get sends a GET request for the URL within the context
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends the request. Idempotent requests without a body are retried with
+// exponential backoff when an attempt times out or the server responds with
+// a 5xx status; the response to the final attempt is returned as is. The
+// response body must be closed, which also releases the attempt timeout.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	timeout := c.timeoutFor(req.URL.Hostname())
+
+	attempts := 1
+	if retryable(req) {
+		attempts += c.retries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return nil, lastErr
+			}
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		out := req.Clone(attemptCtx)
+		if out.Header.Get("User-Agent") == "" {
+			out.Header.Set("User-Agent", UserAgent)
+		}
+
+		start := time.Now()
+		resp, err := c.http.Do(out)
+		c.record(ctx, req, start, resp, err)
+		if err != nil {
+			cancel()
+			// Give up when the caller's context is done or the failure is
+			// not a timeout
+			if ctx.Err() != nil || !isTimeout(err) {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= 500 && attempt < attempts-1 {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			cancel()
+			lastErr = errors.New(resp.Status)
+			continue
+		}
+
+		resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+
+	return nil, lastErr
+}
+
+// record adds an attempt to the trace of the context, passes it to the
+// observer and logs it
+func (c *Client) record(ctx context.Context, req *http.Request, start time.Time, resp *http.Response, err error) {
+	request := Request{Host: req.URL.Hostname(), Duration: time.Since(start)}
+	if err != nil {
+		request.Error = attemptError(err)
+	} else {
+		request.Status = resp.StatusCode
+	}
+	record(ctx, request)
+	if c.observe != nil {
+		c.observe(request)
+	}
+
+	slog.DebugContext(ctx, "upstream request",
+		"method", req.Method,
+		"host", request.Host,
+		"path", req.URL.Path,
+		"status", request.Status,
+		"duration", request.Duration,
+		"error", request.Error,
+	)
+}
+
+// MaxDuration returns the longest a request can take: every attempt timing
+// out at the longest host timeout, with the longest backoff between them
+func (c *Client) MaxDuration() time.Duration {
+	timeout := c.timeout
+	for _, t := range c.hostTimeouts {
+		timeout = max(timeout, t)
+	}
+
+	total := time.Duration(c.retries+1) * timeout
+	for attempt := 1; attempt <= c.retries; attempt++ {
+		total += min(c.backoff<<(attempt-1), maxBackoff)
+	}
+	return total
+}
+
+// attemptError describes the failure of an attempt without the request
+// URL, whose query string may carry credentials (QRZ and HamQTH logins)
+func attemptError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// timeoutFor returns the attempt timeout for a host
+func (c *Client) timeoutFor(host string) time.Duration {
+	if timeout, ok := c.hostTimeouts[host]; ok && timeout > 0 {
+		return timeout
+	}
+	return c.timeout
+}
+
+// wait sleeps before the given retry, returning early when the context is done
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := min(c.backoff<<(attempt-1), maxBackoff)
+	// Jitter spreads out retries from concurrent calls
+	delay = delay/2 + rand.N(delay/2+1)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryable reports whether a request can safely be sent again
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// isTimeout reports whether an attempt failed by timing out
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// cancelBody releases the attempt context when the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,186 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer responds with the given statuses in turn, repeating the last
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestDoRetriesServerErrors(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable, http.StatusOK)
+	client := New(Options{Backoff: time.Millisecond})
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestDoReturnsFinalServerError(t *testing.T) {
+	server, calls := statusServer(t, http.StatusBadGateway)
+	client := New(Options{Retries: 2, Backoff: time.Millisecond})
+
+	resp, err := client.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestDoDoesNotRetryUnsafeRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+	}{
+		{"post", http.MethodPost, ""},
+		{"get with body", http.MethodGet, "query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := statusServer(t, http.StatusServiceUnavailable, http.StatusOK)
+			client := New(Options{Backoff: time.Millisecond})
+
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+			}
+			if got := calls.Load(); got != 1 {
+				t.Errorf("attempts = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestDoAppliesHostTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	var requests []Request
+	client := New(Options{
+		Timeout:      5 * time.Second,
+		HostTimeouts: map[string]time.Duration{"127.0.0.1": 50 * time.Millisecond},
+		Retries:      -1,
+		Observe:      func(r Request) { requests = append(requests, r) },
+	})
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("Get succeeded, want a timeout")
+	}
+	if !isTimeout(err) {
+		t.Errorf("error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Get took %v, want about the 50ms host timeout", elapsed)
+	}
+	if len(requests) != 1 {
+		t.Errorf("attempts = %d, want 1", len(requests))
+	}
+}
+
+func TestDoStopsWhenCanceledDuringBackoff(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel once the first attempt is done, while the client waits to retry
+	client := New(Options{Backoff: 5 * time.Second, Observe: func(Request) { cancel() }})
+
+	start := time.Now()
+	_, err := client.Get(ctx, server.URL)
+	if err == nil {
+		t.Fatal("Get succeeded, want an error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Get took %v, want it to return when canceled", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestDoSetsUserAgent(t *testing.T) {
+	tests := []struct {
+		name  string
+		agent string
+		want  string
+	}{
+		{"default", "", UserAgent},
+		{"caller's own", "logger/2.0", "logger/2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("User-Agent")
+			}))
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.agent != "" {
+				req.Header.Set("User-Agent", tt.agent)
+			}
+			resp, err := New(Options{}).Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+
+			if got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package upstream
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Request is the outcome of one request attempt to an upstream service
+type Request struct {
+	Host     string
+	Status   int
+	Error    string
+	Duration time.Duration
+}
+
+// Trace records the upstream requests made within a context, so that a tool
+// call can report which services it reached and how they responded
+type Trace struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context in which every upstream request attempt is
+// recorded in the returned trace
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+// Requests returns the attempts recorded so far, in order
+func (t *Trace) Requests() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Request(nil), t.requests...)
+}
+
+// record adds an attempt to the trace of the context, if any
+func record(ctx context.Context, request Request) {
+	trace, ok := ctx.Value(traceKey{}).(*Trace)
+	if !ok {
+		return
+	}
+	trace.mu.Lock()
+	trace.requests = append(trace.requests, request)
+	trace.mu.Unlock()
+}